@@ -0,0 +1,128 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package blockdevice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/prometheus/procfs"
+)
+
+const (
+	dmControlPath = "/dev/mapper/control"
+	// dmBufferSize is the initial size of the ioctl buffer, doubled while the
+	// kernel reports it as too small, up to dmMaxBufferSize.
+	dmBufferSize    = 16 * 1024
+	dmMaxBufferSize = 16 * 1024 * 1024
+)
+
+// DMTableStatus returns the status of the targets of the named device-mapper
+// device, as printed by "dmsetup status <name>". It issues the
+// DM_TABLE_STATUS ioctl on /dev/mapper/control, which requires
+// CAP_SYS_ADMIN.
+func DMTableStatus(name string) ([]DMTarget, error) {
+	return dmTableStatus(name, 0)
+}
+
+// DMTable returns the table of the targets of the named device-mapper device,
+// as printed by "dmsetup table <name>". It issues the DM_TABLE_STATUS ioctl on
+// /dev/mapper/control, which requires CAP_SYS_ADMIN.
+func DMTable(name string) ([]DMTarget, error) {
+	return dmTableStatus(name, unix.DM_STATUS_TABLE_FLAG)
+}
+
+func dmTableStatus(name string, flags uint32) ([]DMTarget, error) {
+	if name == "" || len(name) >= unix.DM_NAME_LEN {
+		return nil, fmt.Errorf("invalid device-mapper device name %q", name)
+	}
+
+	f, err := os.OpenFile(dmControlPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	for size := dmBufferSize; size <= dmMaxBufferSize; size *= 2 {
+		// Back the buffer with uint64s to align the ioctl structures.
+		words := make([]uint64, size/8)
+		buf := unsafe.Slice((*byte)(unsafe.Pointer(&words[0])), size)
+
+		dmi := (*unix.DmIoctl)(unsafe.Pointer(&words[0]))
+		dmi.Version = [3]uint32{unix.DM_VERSION_MAJOR, 0, 0}
+		dmi.Data_size = uint32(size)
+		dmi.Data_start = unix.SizeofDmIoctl
+		dmi.Flags = flags
+		copy(dmi.Name[:], name)
+
+		_, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), unix.DM_TABLE_STATUS, uintptr(unsafe.Pointer(&words[0])))
+		if errno != 0 {
+			return nil, fmt.Errorf("DM_TABLE_STATUS ioctl for device-mapper device %q failed: %w", name, errno)
+		}
+		if dmi.Flags&unix.DM_BUFFER_FULL_FLAG != 0 {
+			continue
+		}
+
+		if dmi.Data_start > dmi.Data_size || int(dmi.Data_size) > size {
+			return nil, fmt.Errorf("%w: invalid DM_TABLE_STATUS data range %d-%d", procfs.ErrFileParse, dmi.Data_start, dmi.Data_size)
+		}
+		return parseDMTargetSpecs(buf[dmi.Data_start:dmi.Data_size], int(dmi.Target_count))
+	}
+
+	return nil, fmt.Errorf("DM_TABLE_STATUS result for device-mapper device %q exceeds %d bytes", name, dmMaxBufferSize)
+}
+
+// parseDMTargetSpecs parses the count targets returned by the DM_TABLE_STATUS
+// ioctl. Each target is a struct dm_target_spec followed by its NUL terminated
+// parameters, and the next field of the spec is the offset of the following
+// target from the start of data.
+func parseDMTargetSpecs(data []byte, count int) ([]DMTarget, error) {
+	targets := make([]DMTarget, 0, count)
+
+	var offset uint64
+	for range count {
+		if offset+unix.SizeofDmTargetSpec > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: invalid DM_TABLE_STATUS target offset %d", procfs.ErrFileParse, offset)
+		}
+		// struct dm_target_spec: sector_start, length, status, next and
+		// target_type[DM_MAX_TYPE_NAME].
+		spec := data[offset : offset+unix.SizeofDmTargetSpec]
+		targets = append(targets, DMTarget{
+			Start:  binary.NativeEndian.Uint64(spec[0:8]),
+			Length: binary.NativeEndian.Uint64(spec[8:16]),
+			Type:   cString(spec[24:]),
+			Params: strings.Fields(cString(data[offset+unix.SizeofDmTargetSpec:])),
+		})
+
+		offset = uint64(binary.NativeEndian.Uint32(spec[20:24]))
+	}
+
+	return targets, nil
+}
+
+// cString returns the NUL terminated string at the start of b.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,72 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package blockdevice
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/sys/unix"
+)
+
+// dmTargetSpec encodes a struct dm_target_spec followed by its parameters,
+// padded to 8 bytes as the kernel does, with next relative to base.
+func dmTargetSpec(base int, start, length uint64, typ, params string) []byte {
+	size := (unix.SizeofDmTargetSpec + len(params) + 1 + 7) &^ 7
+	b := make([]byte, size)
+	binary.NativeEndian.PutUint64(b[0:], start)
+	binary.NativeEndian.PutUint64(b[8:], length)
+	binary.NativeEndian.PutUint32(b[20:], uint32(base+size))
+	copy(b[24:24+unix.DM_MAX_TYPE_NAME], typ)
+	copy(b[unix.SizeofDmTargetSpec:], params)
+	return b
+}
+
+func TestParseDMTargetSpecs(t *testing.T) {
+	data := dmTargetSpec(0, 0, 209715200, "thin-pool", "3 1200/4161600 185344/3276800 - rw discard_passdown queue_if_no_space - 1024")
+	data = append(data, dmTargetSpec(len(data), 209715200, 2048, "linear", "")...)
+
+	targets, err := parseDMTargetSpecs(data, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []DMTarget{
+		{
+			Start:  0,
+			Length: 209715200,
+			Type:   "thin-pool",
+			Params: []string{"3", "1200/4161600", "185344/3276800", "-", "rw", "discard_passdown", "queue_if_no_space", "-", "1024"},
+		},
+		{Start: 209715200, Length: 2048, Type: "linear", Params: []string{}},
+	}
+	if diff := cmp.Diff(want, targets); diff != "" {
+		t.Fatalf("unexpected targets (-want +got):\n%s", diff)
+	}
+
+	pool, err := targets[0].ThinPoolStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := uint64(185344), pool.UsedDataBlocks; want != got {
+		t.Errorf("want %d used data blocks, got %d", want, got)
+	}
+
+	if _, err := parseDMTargetSpecs(data[:unix.SizeofDmTargetSpec], 2); err == nil {
+		t.Error("expected an error for a truncated target, but none occurred")
+	}
+}
@@ -0,0 +1,466 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs"
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// DMTarget is a single target line of the table or status of a device-mapper
+// device, as returned by DMTable and DMTableStatus and printed by
+// "dmsetup table" and "dmsetup status".
+type DMTarget struct {
+	// Name is the name of the device-mapper device. It is only set when the
+	// output covers several devices, e.g. "dmsetup status" without a device.
+	Name string
+	// Start and Length are the range of the device mapped by the target, in
+	// sectors.
+	Start  uint64
+	Length uint64
+	// Type is the target type, e.g. "linear", "thin-pool" or "crypt".
+	Type string
+	// Params are the raw target specific parameters.
+	Params []string
+}
+
+// DMThinPoolStatus is the status of a thin-pool target.
+// See Documentation/admin-guide/device-mapper/thin-provisioning.rst in the
+// Linux kernel for more information.
+type DMThinPoolStatus struct {
+	// Fail is true if the pool has failed, in which case the other fields
+	// are not set.
+	Fail                bool
+	TransactionID       uint64
+	UsedMetadataBlocks  uint64
+	TotalMetadataBlocks uint64
+	UsedDataBlocks      uint64
+	TotalDataBlocks     uint64
+	// HeldMetadataRoot is the location of the held metadata root, "-" if no
+	// root is held.
+	HeldMetadataRoot string
+	// Mode is one of "rw", "ro" or "out_of_data_space".
+	Mode            string
+	DiscardPassdown bool
+	// ErrorIfNoSpace is true if IO fails instead of being queued when the
+	// pool runs out of data space.
+	ErrorIfNoSpace bool
+	NeedsCheck     bool
+	// MetadataLowWatermark is the number of free metadata blocks below which
+	// an event is raised. Not reported by kernels before 4.19.
+	MetadataLowWatermark *uint64
+}
+
+// DataUsage returns the fraction of data blocks of the pool in use.
+func (s DMThinPoolStatus) DataUsage() float64 {
+	if s.TotalDataBlocks == 0 {
+		return 0
+	}
+	return float64(s.UsedDataBlocks) / float64(s.TotalDataBlocks)
+}
+
+// MetadataUsage returns the fraction of metadata blocks of the pool in use.
+func (s DMThinPoolStatus) MetadataUsage() float64 {
+	if s.TotalMetadataBlocks == 0 {
+		return 0
+	}
+	return float64(s.UsedMetadataBlocks) / float64(s.TotalMetadataBlocks)
+}
+
+// DMThinStatus is the status of a thin target.
+type DMThinStatus struct {
+	// Fail is true if the thin device has failed, in which case the other
+	// fields are not set.
+	Fail bool
+	// MappedSectors is the number of sectors mapped to the pool.
+	MappedSectors uint64
+	// HighestMappedSector is the highest mapped sector, nil if no sector is
+	// mapped.
+	HighestMappedSector *uint64
+}
+
+// DMCacheStatus is the status of a cache target.
+// See Documentation/admin-guide/device-mapper/cache.rst in the Linux kernel
+// for more information.
+type DMCacheStatus struct {
+	// Fail is true if the cache has failed, in which case the other fields
+	// are not set.
+	Fail bool
+	// MetadataBlockSize is the size of the metadata blocks, in sectors.
+	MetadataBlockSize   uint64
+	UsedMetadataBlocks  uint64
+	TotalMetadataBlocks uint64
+	// CacheBlockSize is the size of the cache blocks, in sectors.
+	CacheBlockSize   uint64
+	UsedCacheBlocks  uint64
+	TotalCacheBlocks uint64
+	ReadHits         uint64
+	ReadMisses       uint64
+	WriteHits        uint64
+	WriteMisses      uint64
+	Demotions        uint64
+	Promotions       uint64
+	Dirty            uint64
+	Features         []string
+	CoreArgs         map[string]string
+	Policy           string
+	PolicyArgs       map[string]string
+	// MetadataMode is one of "rw", "ro" or "fail".
+	MetadataMode string
+	NeedsCheck   bool
+}
+
+// DMRAIDStatus is the status of a raid target.
+// See Documentation/admin-guide/device-mapper/dm-raid.rst in the Linux
+// kernel for more information.
+type DMRAIDStatus struct {
+	// Level is the RAID type, e.g. "raid1" or "raid5_ls".
+	Level   string
+	Devices uint64
+	// Health contains one character per device: 'A' for alive and in-sync,
+	// 'a' for alive but not in-sync and 'D' for dead/failed.
+	Health        string
+	SyncedSectors uint64
+	TotalSectors  uint64
+	// SyncAction is the current sync action, e.g. "idle", "resync",
+	// "recover" or "check".
+	SyncAction    string
+	MismatchCount uint64
+	// DataOffset is the offset of the data on each device, in sectors. Not
+	// reported by target versions before 1.9.0.
+	DataOffset *uint64
+	// Journal is the state of the journal device, "-" if there is none. Not
+	// reported by target versions before 1.9.0.
+	Journal string
+}
+
+// SyncRatio returns the fraction of the array which is in sync.
+func (s DMRAIDStatus) SyncRatio() float64 {
+	if s.TotalSectors == 0 {
+		return 0
+	}
+	return float64(s.SyncedSectors) / float64(s.TotalSectors)
+}
+
+// FailedDevices returns the number of devices marked as dead or failed.
+func (s DMRAIDStatus) FailedDevices() int {
+	return strings.Count(s.Health, "D")
+}
+
+// DMCryptTable is the table of a crypt target. The status of crypt targets
+// has no parameters.
+// See Documentation/admin-guide/device-mapper/dm-crypt.rst in the Linux
+// kernel for more information.
+type DMCryptTable struct {
+	Cipher string
+	// Key is the key as shown by the kernel, usually all zeros or a keyring
+	// reference such as ":64:logon:cryptsetup:<uuid>".
+	Key      string
+	IVOffset uint64
+	// Device is the underlying device, e.g. "8:2" or "/dev/sda2".
+	Device string
+	// Offset is the start of the encrypted data on the device, in sectors.
+	Offset  uint64
+	Options []string
+}
+
+// ParseDMTargets parses the output of "dmsetup table" or "dmsetup status",
+// one target per line, optionally prefixed with the device name.
+//
+// The kernel does not expose the table or status of device-mapper devices in
+// procfs or sysfs, only through the DM_TABLE_STATUS ioctl, which DMTable and
+// DMTableStatus issue directly.
+func ParseDMTargets(r io.Reader) ([]DMTarget, error) {
+	var targets []DMTarget
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+
+		var target DMTarget
+		fields := strings.Fields(line)
+		if name, ok := strings.CutSuffix(fields[0], ":"); ok {
+			target.Name = name
+			fields = fields[1:]
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%w: invalid device-mapper target line %q", procfs.ErrFileParse, line)
+		}
+
+		var err error
+		if target.Start, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid device-mapper target line %q: %w", procfs.ErrFileParse, line, err)
+		}
+		if target.Length, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid device-mapper target line %q: %w", procfs.ErrFileParse, line, err)
+		}
+		target.Type = fields[2]
+		target.Params = fields[3:]
+		targets = append(targets, target)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// ThinPoolStatus parses the status parameters of a thin-pool target.
+func (t DMTarget) ThinPoolStatus() (*DMThinPoolStatus, error) {
+	if err := t.checkType("thin-pool"); err != nil {
+		return nil, err
+	}
+	p := dmParams{params: t.Params}
+	if p.failed() {
+		return &DMThinPoolStatus{Fail: true}, nil
+	}
+
+	var s DMThinPoolStatus
+	s.TransactionID = p.uint()
+	s.UsedMetadataBlocks, s.TotalMetadataBlocks = p.ratio()
+	s.UsedDataBlocks, s.TotalDataBlocks = p.ratio()
+	s.HeldMetadataRoot = p.string()
+	s.Mode = p.string()
+	s.DiscardPassdown = p.string() == "discard_passdown"
+	s.ErrorIfNoSpace = p.string() == "error_if_no_space"
+	if p.remaining() > 0 {
+		s.NeedsCheck = p.string() == "needs_check"
+	}
+	if p.remaining() > 0 {
+		v := p.uint()
+		s.MetadataLowWatermark = &v
+	}
+	if err := p.finish(t); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// ThinStatus parses the status parameters of a thin target.
+func (t DMTarget) ThinStatus() (*DMThinStatus, error) {
+	if err := t.checkType("thin"); err != nil {
+		return nil, err
+	}
+	p := dmParams{params: t.Params}
+	if p.failed() {
+		return &DMThinStatus{Fail: true}, nil
+	}
+
+	var s DMThinStatus
+	s.MappedSectors = p.uint()
+	if highest := p.string(); highest != "-" {
+		v, err := strconv.ParseUint(highest, 10, 64)
+		if err != nil {
+			p.err = err
+		}
+		s.HighestMappedSector = &v
+	}
+	if err := p.finish(t); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CacheStatus parses the status parameters of a cache target.
+func (t DMTarget) CacheStatus() (*DMCacheStatus, error) {
+	if err := t.checkType("cache"); err != nil {
+		return nil, err
+	}
+	p := dmParams{params: t.Params}
+	if p.failed() {
+		return &DMCacheStatus{Fail: true}, nil
+	}
+
+	var s DMCacheStatus
+	s.MetadataBlockSize = p.uint()
+	s.UsedMetadataBlocks, s.TotalMetadataBlocks = p.ratio()
+	s.CacheBlockSize = p.uint()
+	s.UsedCacheBlocks, s.TotalCacheBlocks = p.ratio()
+	for _, v := range []*uint64{
+		&s.ReadHits, &s.ReadMisses, &s.WriteHits, &s.WriteMisses,
+		&s.Demotions, &s.Promotions, &s.Dirty,
+	} {
+		*v = p.uint()
+	}
+	s.Features = p.strings(p.uint())
+	s.CoreArgs = dmArgs(p.strings(p.uint()))
+	s.Policy = p.string()
+	s.PolicyArgs = dmArgs(p.strings(p.uint()))
+	s.MetadataMode = p.string()
+	s.NeedsCheck = p.string() == "needs_check"
+	if err := p.finish(t); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// RAIDStatus parses the status parameters of a raid target.
+func (t DMTarget) RAIDStatus() (*DMRAIDStatus, error) {
+	if err := t.checkType("raid"); err != nil {
+		return nil, err
+	}
+	p := dmParams{params: t.Params}
+
+	var s DMRAIDStatus
+	s.Level = p.string()
+	s.Devices = p.uint()
+	s.Health = p.string()
+	s.SyncedSectors, s.TotalSectors = p.ratio()
+	s.SyncAction = p.string()
+	s.MismatchCount = p.uint()
+	if p.remaining() > 0 {
+		v := p.uint()
+		s.DataOffset = &v
+	}
+	if p.remaining() > 0 {
+		s.Journal = p.string()
+	}
+	if err := p.finish(t); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CryptTable parses the table parameters of a crypt target.
+func (t DMTarget) CryptTable() (*DMCryptTable, error) {
+	if err := t.checkType("crypt"); err != nil {
+		return nil, err
+	}
+	p := dmParams{params: t.Params}
+
+	var c DMCryptTable
+	c.Cipher = p.string()
+	c.Key = p.string()
+	c.IVOffset = p.uint()
+	c.Device = p.string()
+	c.Offset = p.uint()
+	if p.remaining() > 0 {
+		c.Options = p.strings(p.uint())
+	}
+	if err := p.finish(t); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (t DMTarget) checkType(want string) error {
+	if t.Type != want {
+		return fmt.Errorf("device-mapper target is of type %q, not %q", t.Type, want)
+	}
+	return nil
+}
+
+// dmParams consumes device-mapper target parameters, recording the first
+// error encountered.
+type dmParams struct {
+	params []string
+	err    error
+}
+
+// failed reports whether the parameters indicate a failed target.
+func (p *dmParams) failed() bool {
+	return len(p.params) == 1 && (p.params[0] == "Fail" || p.params[0] == "Error")
+}
+
+func (p *dmParams) remaining() int {
+	return len(p.params)
+}
+
+func (p *dmParams) string() string {
+	if p.err != nil {
+		return ""
+	}
+	if len(p.params) == 0 {
+		p.err = io.ErrUnexpectedEOF
+		return ""
+	}
+	s := p.params[0]
+	p.params = p.params[1:]
+	return s
+}
+
+func (p *dmParams) strings(n uint64) []string {
+	ss := make([]string, 0, min(n, uint64(len(p.params))))
+	for i := uint64(0); i < n && p.err == nil; i++ {
+		ss = append(ss, p.string())
+	}
+	return ss
+}
+
+func (p *dmParams) uint() uint64 {
+	s := p.string()
+	if p.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		p.err = err
+	}
+	return v
+}
+
+// ratio parses a "<used>/<total>" parameter.
+func (p *dmParams) ratio() (uint64, uint64) {
+	s := p.string()
+	if p.err != nil {
+		return 0, 0
+	}
+	used, total, ok := strings.Cut(s, "/")
+	if !ok {
+		p.err = fmt.Errorf("invalid ratio %q", s)
+		return 0, 0
+	}
+	values, err := util.ParseUint64s([]string{used, total})
+	if err != nil {
+		p.err = err
+		return 0, 0
+	}
+	return values[0], values[1]
+}
+
+// finish returns the first error encountered, if any, or an error if not all
+// parameters were consumed.
+func (p *dmParams) finish(t DMTarget) error {
+	if p.err == nil && len(p.params) > 0 {
+		p.err = fmt.Errorf("unexpected parameters %q", p.params)
+	}
+	if p.err != nil {
+		return fmt.Errorf("%w: invalid %s parameters %q: %w", procfs.ErrFileParse, t.Type, strings.Join(t.Params, " "), p.err)
+	}
+	return nil
+}
+
+// dmArgs converts a list of alternating keys and values into a map.
+func dmArgs(args []string) map[string]string {
+	m := make(map[string]string, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		m[args[i]] = args[i+1]
+	}
+	return m
+}
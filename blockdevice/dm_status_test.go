@@ -0,0 +1,201 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func uint64p(v uint64) *uint64 {
+	return &v
+}
+
+func TestParseDMTargets(t *testing.T) {
+	status := `vg0-pool: 0 209715200 thin-pool 3 1200/4161600 185344/3276800 - rw discard_passdown queue_if_no_space - 1024
+vg0-thin1: 0 41943040 thin 12582912 41943039
+vg0-thin2: 0 41943040 thin 0 -
+vg0-cached: 0 41943040 cache 8 259/4096 128 1024/65536 1234 567 890 12 3 4 5 1 writeback 2 migration_threshold 2048 smq 0 rw -
+vg0-mirror: 0 2097152 raid raid1 2 aD 1048576/2097152 recover 0 0 -
+vg0-old: 0 2097152 raid raid5_ls 3 AAA 2097152/2097152 idle 0
+vg0-failed: 0 209715200 thin-pool Fail
+`
+	targets, err := ParseDMTargets(strings.NewReader(status))
+	if err != nil {
+		t.Fatalf("failed to parse device-mapper status: %v", err)
+	}
+	if want, have := 7, len(targets); want != have {
+		t.Fatalf("want %d targets, have %d", want, have)
+	}
+
+	pool, err := targets[0].ThinPoolStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPool := &DMThinPoolStatus{
+		TransactionID:        3,
+		UsedMetadataBlocks:   1200,
+		TotalMetadataBlocks:  4161600,
+		UsedDataBlocks:       185344,
+		TotalDataBlocks:      3276800,
+		HeldMetadataRoot:     "-",
+		Mode:                 "rw",
+		DiscardPassdown:      true,
+		MetadataLowWatermark: uint64p(1024),
+	}
+	if diff := cmp.Diff(wantPool, pool); diff != "" {
+		t.Errorf("unexpected thin-pool status (-want +got):\n%s", diff)
+	}
+	if want, have := 0.0565625, pool.DataUsage(); want != have {
+		t.Errorf("want data usage %v, have %v", want, have)
+	}
+
+	for i, want := range []*DMThinStatus{
+		{MappedSectors: 12582912, HighestMappedSector: uint64p(41943039)},
+		{},
+	} {
+		thin, err := targets[i+1].ThinStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, thin); diff != "" {
+			t.Errorf("unexpected thin status (-want +got):\n%s", diff)
+		}
+	}
+
+	cache, err := targets[3].CacheStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCache := &DMCacheStatus{
+		MetadataBlockSize:   8,
+		UsedMetadataBlocks:  259,
+		TotalMetadataBlocks: 4096,
+		CacheBlockSize:      128,
+		UsedCacheBlocks:     1024,
+		TotalCacheBlocks:    65536,
+		ReadHits:            1234,
+		ReadMisses:          567,
+		WriteHits:           890,
+		WriteMisses:         12,
+		Demotions:           3,
+		Promotions:          4,
+		Dirty:               5,
+		Features:            []string{"writeback"},
+		CoreArgs:            map[string]string{"migration_threshold": "2048"},
+		Policy:              "smq",
+		PolicyArgs:          map[string]string{},
+		MetadataMode:        "rw",
+	}
+	if diff := cmp.Diff(wantCache, cache); diff != "" {
+		t.Errorf("unexpected cache status (-want +got):\n%s", diff)
+	}
+
+	raid, err := targets[4].RAIDStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRAID := &DMRAIDStatus{
+		Level:         "raid1",
+		Devices:       2,
+		Health:        "aD",
+		SyncedSectors: 1048576,
+		TotalSectors:  2097152,
+		SyncAction:    "recover",
+		DataOffset:    uint64p(0),
+		Journal:       "-",
+	}
+	if diff := cmp.Diff(wantRAID, raid); diff != "" {
+		t.Errorf("unexpected raid status (-want +got):\n%s", diff)
+	}
+	if raid.SyncRatio() != 0.5 || raid.FailedDevices() != 1 {
+		t.Errorf("unexpected sync ratio %v or failed devices %d", raid.SyncRatio(), raid.FailedDevices())
+	}
+
+	raid, err = targets[5].RAIDStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raid.DataOffset != nil || raid.Journal != "" || raid.SyncRatio() != 1 {
+		t.Errorf("unexpected raid status of an old target: %+v", raid)
+	}
+
+	pool, err = targets[6].ThinPoolStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&DMThinPoolStatus{Fail: true}, pool); diff != "" {
+		t.Errorf("unexpected failed thin-pool status (-want +got):\n%s", diff)
+	}
+}
+
+func TestDMCryptTable(t *testing.T) {
+	targets, err := ParseDMTargets(strings.NewReader("0 1951870976 crypt aes-xts-plain64 :64:logon:cryptsetup:4f1c2a3b-5d6e-4f70-8192-a3b4c5d6e7f8-d0 0 8:2 32768 2 allow_discards no_read_workqueue\n"))
+	if err != nil {
+		t.Fatalf("failed to parse device-mapper table: %v", err)
+	}
+	crypt, err := targets[0].CryptTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &DMCryptTable{
+		Cipher:   "aes-xts-plain64",
+		Key:      ":64:logon:cryptsetup:4f1c2a3b-5d6e-4f70-8192-a3b4c5d6e7f8-d0",
+		IVOffset: 0,
+		Device:   "8:2",
+		Offset:   32768,
+		Options:  []string{"allow_discards", "no_read_workqueue"},
+	}
+	if diff := cmp.Diff(want, crypt); diff != "" {
+		t.Errorf("unexpected crypt table (-want +got):\n%s", diff)
+	}
+}
+
+func TestDMTargetsInvalid(t *testing.T) {
+	for _, s := range []string{
+		"0 2097152",
+		"x 2097152 linear 8:0 0",
+	} {
+		if _, err := ParseDMTargets(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+
+	for _, target := range []DMTarget{
+		{Type: "thin-pool", Params: strings.Fields("3 1200 185344/3276800 - rw discard_passdown queue_if_no_space -")},
+		{Type: "thin-pool", Params: strings.Fields("3 1200/4161600 185344/3276800 - rw discard_passdown queue_if_no_space - 1024 extra")},
+		{Type: "thin", Params: strings.Fields("12582912 x")},
+		{Type: "cache", Params: strings.Fields("8 259/4096 128 1024/65536 1234 567 890 12 3 4 5 9 writeback")},
+		{Type: "raid", Params: strings.Fields("raid1 2 AA")},
+		{Type: "linear", Params: strings.Fields("8:0 0")},
+	} {
+		var err error
+		switch target.Type {
+		case "thin-pool", "linear":
+			_, err = target.ThinPoolStatus()
+		case "thin":
+			_, err = target.ThinStatus()
+		case "cache":
+			_, err = target.CacheStatus()
+		case "raid":
+			_, err = target.RAIDStatus()
+		}
+		if err == nil {
+			t.Errorf("expected an error parsing %s %q", target.Type, target.Params)
+		}
+	}
+}
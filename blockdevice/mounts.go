@@ -0,0 +1,201 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/procfs"
+)
+
+// MountIOStats combines a mounted filesystem with the IO statistics of the
+// block devices backing it.
+type MountIOStats struct {
+	// Mount is the mountinfo entry of the filesystem.
+	Mount *procfs.MountInfo
+	// Device contains the Diskstats of the block device the filesystem is
+	// mounted from, e.g. a partition or a device-mapper device.
+	Device Diskstats
+	// PhysicalDevices contains the Diskstats of the whole disks Device is
+	// ultimately built from, see BlockDeviceTopology.PhysicalDevices.
+	PhysicalDevices []Diskstats
+}
+
+// blockDeviceResolver joins mounts with the block device topology and
+// diskstats.
+type blockDeviceResolver struct {
+	topology  BlockDeviceTopology
+	diskstats map[string]Diskstats
+}
+
+func (fs FS) newBlockDeviceResolver() (*blockDeviceResolver, error) {
+	topology, err := fs.SysBlockDeviceTopology()
+	if err != nil {
+		return nil, err
+	}
+	diskstats, err := fs.ProcDiskstats()
+	if err != nil {
+		return nil, err
+	}
+	r := &blockDeviceResolver{
+		topology:  topology,
+		diskstats: make(map[string]Diskstats, len(diskstats)),
+	}
+	for _, d := range diskstats {
+		r.diskstats[d.DeviceName] = d
+	}
+	return r, nil
+}
+
+// mounts returns the mountinfo entries of the current process.
+func (fs FS) mounts() ([]*procfs.MountInfo, error) {
+	pfs, err := procfs.NewFS(fs.proc.Path())
+	if err != nil {
+		return nil, err
+	}
+	return pfs.GetMounts()
+}
+
+// MountIOStats returns the MountIOStats of the filesystem containing the
+// given absolute path, which may be a mountpoint or any path below it. The
+// path is matched against /proc/self/mountinfo without resolving symlinks.
+// An error is returned if the filesystem is not backed by a block device.
+func (fs FS) MountIOStats(path string) (MountIOStats, error) {
+	mounts, err := fs.mounts()
+	if err != nil {
+		return MountIOStats{}, err
+	}
+	mount := findMount(mounts, path)
+	if mount == nil {
+		return MountIOStats{}, fmt.Errorf("no mount found for %q", path)
+	}
+
+	r, err := fs.newBlockDeviceResolver()
+	if err != nil {
+		return MountIOStats{}, err
+	}
+	stats, ok, err := r.mountIOStats(mount)
+	if err != nil {
+		return MountIOStats{}, err
+	}
+	if !ok {
+		return MountIOStats{}, fmt.Errorf("mount %q is not backed by a block device", mount.MountPoint)
+	}
+	return stats, nil
+}
+
+// AllMountIOStats returns the MountIOStats of every mounted filesystem backed
+// by a block device. Bind mounts of the same device are returned as separate
+// entries sharing the same statistics.
+func (fs FS) AllMountIOStats() ([]MountIOStats, error) {
+	mounts, err := fs.mounts()
+	if err != nil {
+		return nil, err
+	}
+	r, err := fs.newBlockDeviceResolver()
+	if err != nil {
+		return nil, err
+	}
+
+	var all []MountIOStats
+	for _, mount := range mounts {
+		stats, ok, err := r.mountIOStats(mount)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			all = append(all, stats)
+		}
+	}
+	return all, nil
+}
+
+// findMount returns the mount whose mountpoint is the longest prefix of path.
+// When several filesystems are mounted on the same mountpoint the last one,
+// which hides the others, is returned.
+func findMount(mounts []*procfs.MountInfo, path string) *procfs.MountInfo {
+	path = filepath.Clean(path)
+	var (
+		found      *procfs.MountInfo
+		foundPoint string
+	)
+	for _, m := range mounts {
+		mountPoint := unescapeMountPoint(m.MountPoint)
+		if mountPoint != "/" && path != mountPoint && !strings.HasPrefix(path, mountPoint+"/") {
+			continue
+		}
+		if found == nil || len(mountPoint) >= len(foundPoint) {
+			found, foundPoint = m, mountPoint
+		}
+	}
+	return found
+}
+
+// unescapeMountPoint decodes the octal escapes used in mountinfo for space,
+// tab, newline and backslash, e.g. "\040" for a space.
+func unescapeMountPoint(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(s)
+}
+
+// device returns the block device backing a mount. The device is looked up
+// by the mount's device number first. Filesystems such as btrfs report an
+// anonymous device number, in which case the /dev path of the mount source
+// is used instead.
+func (r *blockDeviceResolver) device(mount *procfs.MountInfo) (BlockDeviceNode, bool) {
+	var major, minor uint32
+	if _, err := fmt.Sscanf(mount.MajorMinorVer, "%d:%d", &major, &minor); err == nil {
+		if node, ok := r.topology.DeviceByNumber(major, minor); ok {
+			return node, true
+		}
+	}
+
+	if dmName, ok := strings.CutPrefix(mount.Source, "/dev/mapper/"); ok {
+		for _, node := range r.topology {
+			if node.DMName == dmName {
+				return node, true
+			}
+		}
+		return BlockDeviceNode{}, false
+	}
+	if name, ok := strings.CutPrefix(mount.Source, "/dev/"); ok {
+		node, ok := r.topology[name]
+		return node, ok
+	}
+	return BlockDeviceNode{}, false
+}
+
+func (r *blockDeviceResolver) mountIOStats(mount *procfs.MountInfo) (MountIOStats, bool, error) {
+	node, ok := r.device(mount)
+	if !ok {
+		return MountIOStats{}, false, nil
+	}
+
+	stats := MountIOStats{Mount: mount}
+	if stats.Device, ok = r.diskstats[node.DeviceName]; !ok {
+		return MountIOStats{}, false, fmt.Errorf("no diskstats found for %s", node.DeviceName)
+	}
+	for _, name := range r.topology.PhysicalDevices(node.DeviceName) {
+		d, ok := r.diskstats[name]
+		if !ok {
+			return MountIOStats{}, false, fmt.Errorf("no diskstats found for %s", name)
+		}
+		stats.PhysicalDevices = append(stats.PhysicalDevices, d)
+	}
+	return stats, true, nil
+}
@@ -0,0 +1,75 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMountIOStats(t *testing.T) {
+	blockdevice, err := NewFS(procfsFixtures, sysfsFixtures)
+	if err != nil {
+		t.Fatalf("failed to access blockdevice fs: %v", err)
+	}
+
+	tests := []struct {
+		path       string
+		mountPoint string
+		device     string
+		physical   []string
+		readIOs    uint64
+	}{
+		{path: "/", mountPoint: "/", device: "sda1", physical: []string{"sda"}, readIOs: 250},
+		{path: "/etc/hosts", mountPoint: "/", device: "sda1", physical: []string{"sda"}, readIOs: 250},
+		{path: "/data/db/", mountPoint: "/data", device: "dm-0", physical: []string{"sda"}, readIOs: 59910002},
+		{path: "/srv/bind", mountPoint: "/srv/bind", device: "sdb", physical: []string{"sdb"}, readIOs: 326552},
+		{path: "/mnt/backup disk/2024", mountPoint: `/mnt/backup\040disk`, device: "sdb", physical: []string{"sdb"}, readIOs: 326552},
+	}
+	for _, tt := range tests {
+		stats, err := blockdevice.MountIOStats(tt.path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if stats.Mount.MountPoint != tt.mountPoint {
+			t.Errorf(failMsgFormat, "Incorrect mountpoint for "+tt.path, tt.mountPoint, stats.Mount.MountPoint)
+		}
+		if stats.Device.DeviceName != tt.device {
+			t.Errorf(failMsgFormat, "Incorrect device for "+tt.path, tt.device, stats.Device.DeviceName)
+		}
+		if stats.Device.ReadIOs != tt.readIOs {
+			t.Errorf(failMsgFormat, "Incorrect read I/Os for "+tt.path, tt.readIOs, stats.Device.ReadIOs)
+		}
+		var physical []string
+		for _, d := range stats.PhysicalDevices {
+			physical = append(physical, d.DeviceName)
+		}
+		if diff := cmp.Diff(tt.physical, physical); diff != "" {
+			t.Errorf("unexpected physical devices for %s (-want +got):\n%s", tt.path, diff)
+		}
+	}
+
+	if _, err := blockdevice.MountIOStats("/mnt/nfs/test"); err == nil {
+		t.Error("expected error for NFS mount, got nil")
+	}
+
+	all, err := blockdevice.AllMountIOStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 4, len(all); want != got {
+		t.Errorf(failMsgFormat, "Incorrect number of block device mounts", want, got)
+	}
+}
@@ -11,6 +11,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package blockdevice provides functions to retrieve block device statistics
+// from /proc/diskstats and /sys/block.
 package blockdevice
 
 import (
@@ -19,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/prometheus/procfs"
@@ -170,6 +174,16 @@ type BlockQueueStats struct {
 	// ChunksSectors for RAID is the size in 512B sectors of the RAID volume stripe segment,
 	// for zoned host device is the size in 512B sectors.
 	ChunkSectors uint64
+	// ZoneAppendMaxBytes is the maximum number of bytes that can be written to a sequential zone
+	// in a single zone append command, 0 for regular block devices.
+	ZoneAppendMaxBytes uint64
+	// ZoneWriteGranularity is the alignment in bytes of writes to sequential zones.
+	ZoneWriteGranularity uint64
+	// MaxOpenZones is the maximum number of zones that can be explicitly or implicitly open,
+	// 0 means no limit.
+	MaxOpenZones uint64
+	// MaxActiveZones is the maximum number of zones that can be open or closed, 0 means no limit.
+	MaxActiveZones uint64
 	// FUA indicates whether the device supports Force Unit Access for write requests.
 	FUA uint64
 	// MaxDiscardSegments is the maximum number of DMA entries in a discard request.
@@ -179,6 +193,48 @@ type BlockQueueStats struct {
 	WriteZeroesMaxBytes uint64
 }
 
+// BlockInflight models the /sys/block/<device>/inflight file, the number of
+// requests currently in flight in the block layer.
+type BlockInflight struct {
+	// Read is the number of read requests in flight.
+	Read uint64
+	// Write is the number of write requests in flight.
+	Write uint64
+}
+
+// BlockIntegrity models the integrity files that are located in the sysfs tree for each block device
+// and described in the kernel documentation:
+// https://www.kernel.org/doc/Documentation/ABI/testing/sysfs-block
+type BlockIntegrity struct {
+	// DeviceIsIntegrityCapable indicates whether the storage device is capable of storing
+	// integrity metadata (1 is on, 0 is off).
+	DeviceIsIntegrityCapable uint64
+	// Format is the metadata format used, "none" if no integrity profile is registered.
+	Format string
+	// ProtectionIntervalBytes is the number of bytes of data protected by one integrity tuple.
+	ProtectionIntervalBytes uint64
+	// ReadVerify indicates whether the block layer should verify the integrity of read requests
+	// (1 is on, 0 is off).
+	ReadVerify uint64
+	// TagSize is the number of bytes of integrity tag space available per protection interval.
+	TagSize uint64
+	// WriteGenerate indicates whether the block layer should automatically generate checksums
+	// for write requests (1 is on, 0 is off).
+	WriteGenerate uint64
+}
+
+// BlockMQHardwareQueue models a blk-mq hardware queue located at /sys/block/<device>/mq/<index>.
+type BlockMQHardwareQueue struct {
+	// Index is the hardware queue index.
+	Index int
+	// CPUList is the list of CPUs mapped to the hardware queue.
+	CPUList []int
+	// NRTags is the number of tags of the hardware queue.
+	NRTags uint64
+	// NRReservedTags is the number of reserved tags of the hardware queue.
+	NRReservedTags uint64
+}
+
 type IODeviceStats struct {
 	IODoneCount uint64
 	IOErrCount  uint64
@@ -213,6 +269,9 @@ const (
 	sysBlockPath        = "block"
 	sysBlockStatFormat  = "%d %d %d %d %d %d %d %d %d %d %d %d %d %d %d %d %d"
 	sysBlockQueue       = "queue"
+	sysBlockInflight    = "inflight"
+	sysBlockIntegrity   = "integrity"
+	sysBlockMQ          = "mq"
 	sysBlockDM          = "dm"
 	sysUnderlyingDev    = "slaves"
 	sysBlockSize        = "size"
@@ -433,6 +492,22 @@ func (fs FS) SysBlockDeviceQueueStats(device string) (BlockQueueStats, error) {
 		schedulers = append(schedulers, s)
 	}
 	stat.SchedulerList = schedulers
+	// Zone files missing on older kernels are left at zero.
+	for file, p := range map[string]*uint64{
+		"zone_append_max_bytes":  &stat.ZoneAppendMaxBytes,
+		"zone_write_granularity": &stat.ZoneWriteGranularity,
+		"max_open_zones":         &stat.MaxOpenZones,
+		"max_active_zones":       &stat.MaxActiveZones,
+	} {
+		val, err := util.ReadUintFromFile(fs.sys.Path(sysBlockPath, device, sysBlockQueue, file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return BlockQueueStats{}, err
+		}
+		*p = val
+	}
 	// optional
 	throttleSampleTime, err := util.ReadUintFromFile(fs.sys.Path(sysBlockPath, device, sysBlockQueue, "throttle_sample_time"))
 	if err == nil {
@@ -441,6 +516,93 @@ func (fs FS) SysBlockDeviceQueueStats(device string) (BlockQueueStats, error) {
 	return stat, nil
 }
 
+// SysBlockDeviceInflight returns the number of in flight requests for the block device
+// read from /sys/block/<device>/inflight.
+func (fs FS) SysBlockDeviceInflight(device string) (BlockInflight, error) {
+	data, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, sysBlockInflight))
+	if err != nil {
+		return BlockInflight{}, err
+	}
+	inflight := BlockInflight{}
+	_, err = fmt.Sscanf(data, "%d %d", &inflight.Read, &inflight.Write)
+	if err != nil {
+		return BlockInflight{}, fmt.Errorf("%w: failed to parse inflight for %s: %w", procfs.ErrFileParse, device, err)
+	}
+	return inflight, nil
+}
+
+// SysBlockDeviceIntegrity returns the integrity settings for /sys/block/xxx/integrity where xxx is a device name.
+func (fs FS) SysBlockDeviceIntegrity(device string) (BlockIntegrity, error) {
+	integrity := BlockIntegrity{}
+	// Files with uint64 fields
+	for file, p := range map[string]*uint64{
+		"device_is_integrity_capable": &integrity.DeviceIsIntegrityCapable,
+		"protection_interval_bytes":   &integrity.ProtectionIntervalBytes,
+		"read_verify":                 &integrity.ReadVerify,
+		"tag_size":                    &integrity.TagSize,
+		"write_generate":              &integrity.WriteGenerate,
+	} {
+		val, err := util.ReadUintFromFile(fs.sys.Path(sysBlockPath, device, sysBlockIntegrity, file))
+		if err != nil {
+			return BlockIntegrity{}, err
+		}
+		*p = val
+	}
+	format, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, sysBlockIntegrity, "format"))
+	if err != nil {
+		return BlockIntegrity{}, err
+	}
+	integrity.Format = format
+	return integrity, nil
+}
+
+// SysBlockDeviceMQHardwareQueues returns the blk-mq hardware queues of the block device read from
+// /sys/block/<device>/mq/<index>, sorted by index.
+func (fs FS) SysBlockDeviceMQHardwareQueues(device string) ([]BlockMQHardwareQueue, error) {
+	entries, err := os.ReadDir(fs.sys.Path(sysBlockPath, device, sysBlockMQ))
+	if err != nil {
+		return nil, err
+	}
+	queues := make([]BlockMQHardwareQueue, 0, len(entries))
+	for _, entry := range entries {
+		index, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		queue := BlockMQHardwareQueue{Index: index}
+		cpuList, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, sysBlockMQ, entry.Name(), "cpu_list"))
+		if err != nil {
+			return nil, err
+		}
+		for cpu := range strings.SplitSeq(cpuList, ",") {
+			cpu = strings.TrimSpace(cpu)
+			if cpu == "" {
+				continue
+			}
+			id, err := strconv.Atoi(cpu)
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to parse cpu_list for %s: %w", procfs.ErrFileParse, device, err)
+			}
+			queue.CPUList = append(queue.CPUList, id)
+		}
+		for file, p := range map[string]*uint64{
+			"nr_tags":          &queue.NRTags,
+			"nr_reserved_tags": &queue.NRReservedTags,
+		} {
+			val, err := util.ReadUintFromFile(fs.sys.Path(sysBlockPath, device, sysBlockMQ, entry.Name(), file))
+			if err != nil {
+				return nil, err
+			}
+			*p = val
+		}
+		queues = append(queues, queue)
+	}
+	slices.SortFunc(queues, func(a, b BlockMQHardwareQueue) int {
+		return a.Index - b.Index
+	})
+	return queues, nil
+}
+
 func (fs FS) SysBlockDeviceMapperInfo(device string) (DeviceMapperInfo, error) {
 	info := DeviceMapperInfo{}
 	// Files with uint64 fields
@@ -75,7 +75,7 @@ func TestBlockDevice(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	expectedNumOfDevices := 16
+	expectedNumOfDevices := 17
 	if len(devices) != expectedNumOfDevices {
 		t.Fatalf(failMsgFormat, "Incorrect number of devices", expectedNumOfDevices, len(devices))
 	}
@@ -267,3 +267,99 @@ func TestSysBlockDeviceRotational(t *testing.T) {
 		t.Errorf("expected error reading rotational for %s (no queue dir), got nil", devices[0])
 	}
 }
+
+func TestSysBlockDeviceInflight(t *testing.T) {
+	blockdevice, err := NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access blockdevice fs: %v", err)
+	}
+	inflight, err := blockdevice.SysBlockDeviceInflight("sda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(BlockInflight{Read: 3, Write: 7}, inflight); diff != "" {
+		t.Fatalf("unexpected BlockInflight (-want +got):\n%s", diff)
+	}
+}
+
+func TestSysBlockDeviceIntegrity(t *testing.T) {
+	blockdevice, err := NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access blockdevice fs: %v", err)
+	}
+	integrity, err := blockdevice.SysBlockDeviceIntegrity("sda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	integrityExpected := BlockIntegrity{
+		Format:        "none",
+		ReadVerify:    1,
+		WriteGenerate: 1,
+	}
+	if diff := cmp.Diff(integrityExpected, integrity); diff != "" {
+		t.Fatalf("unexpected BlockIntegrity (-want +got):\n%s", diff)
+	}
+}
+
+func TestSysBlockDeviceQueueStatsZoned(t *testing.T) {
+	blockdevice, err := NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access blockdevice fs: %v", err)
+	}
+	stats, err := blockdevice.SysBlockDeviceQueueStats("sde")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type zoneInfo struct {
+		Zoned                string
+		NRZones              uint64
+		ChunkSectors         uint64
+		ZoneAppendMaxBytes   uint64
+		ZoneWriteGranularity uint64
+		MaxOpenZones         uint64
+		MaxActiveZones       uint64
+	}
+	want := zoneInfo{
+		Zoned:                "host-managed",
+		NRZones:              55880,
+		ChunkSectors:         524288,
+		ZoneAppendMaxBytes:   1310720,
+		ZoneWriteGranularity: 4096,
+		MaxOpenZones:         128,
+		MaxActiveZones:       0,
+	}
+	got := zoneInfo{
+		Zoned:                stats.Zoned,
+		NRZones:              stats.NRZones,
+		ChunkSectors:         stats.ChunkSectors,
+		ZoneAppendMaxBytes:   stats.ZoneAppendMaxBytes,
+		ZoneWriteGranularity: stats.ZoneWriteGranularity,
+		MaxOpenZones:         stats.MaxOpenZones,
+		MaxActiveZones:       stats.MaxActiveZones,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected zone queue stats (-want +got):\n%s", diff)
+	}
+	if stats.SchedulerCurrent != "mq-deadline" {
+		t.Errorf("unexpected scheduler for zoned device: want mq-deadline, got %s", stats.SchedulerCurrent)
+	}
+}
+
+func TestSysBlockDeviceMQHardwareQueues(t *testing.T) {
+	blockdevice, err := NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access blockdevice fs: %v", err)
+	}
+	queues, err := blockdevice.SysBlockDeviceMQHardwareQueues("sda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queuesExpected := []BlockMQHardwareQueue{
+		{Index: 0, CPUList: []int{0, 1, 2, 3}, NRTags: 64},
+		{Index: 1, CPUList: []int{4, 5, 6, 7}, NRTags: 64, NRReservedTags: 1},
+		{Index: 10, CPUList: []int{8}, NRTags: 32},
+	}
+	if diff := cmp.Diff(queuesExpected, queues); diff != "" {
+		t.Fatalf("unexpected BlockMQHardwareQueues (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs"
+	"github.com/prometheus/procfs/internal/util"
+)
+
+const (
+	sysBlockDev       = "dev"
+	sysBlockHolders   = "holders"
+	sysBlockPartition = "partition"
+	sysBlockRemovable = "removable"
+	sysBlockRO        = "ro"
+)
+
+// BlockDeviceNode describes a single block device or partition found under
+// /sys/block together with its relations to other block devices.
+type BlockDeviceNode struct {
+	Info
+	// Parent is the name of the whole disk a partition belongs to. It is
+	// empty for whole disks.
+	Parent string
+	// PartitionNumber is the partition number read from <partition>/partition.
+	// It is 0 for whole disks.
+	PartitionNumber uint64
+	// SizeBytes is the device size in bytes (sectors × 512).
+	SizeBytes uint64
+	// Removable is true when removable reads "1". Only whole disks report
+	// this attribute, partitions inherit the value of their parent.
+	Removable bool
+	// ReadOnly is true when ro reads "1".
+	ReadOnly bool
+	// DMName is the device-mapper name (from dm/name), empty if the device
+	// is not a device-mapper device.
+	DMName string
+	// Partitions lists the names of the partitions of a whole disk.
+	Partitions []string
+	// Holders lists the devices built on top of this device, e.g. the
+	// dm or md devices using it, read from the holders/ directory.
+	Holders []string
+	// Slaves lists the devices this device is built from, read from the
+	// slaves/ directory.
+	Slaves []string
+}
+
+// IsPartition returns true if the node is a partition of a whole disk.
+func (n BlockDeviceNode) IsPartition() bool {
+	return n.Parent != ""
+}
+
+// BlockDeviceTopology is the graph of all block devices and partitions
+// indexed by their kernel name, e.g. "sda", "sda1" or "dm-0".
+type BlockDeviceTopology map[string]BlockDeviceNode
+
+// SysBlockDeviceTopology builds the BlockDeviceTopology of every device in
+// /sys/block and its partitions.
+func (fs FS) SysBlockDeviceTopology() (BlockDeviceTopology, error) {
+	devices, err := fs.SysBlockDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	topology := make(BlockDeviceTopology)
+	for _, device := range devices {
+		disk, err := fs.readBlockDeviceNode(device)
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := os.ReadDir(fs.sys.Path(sysBlockPath, device))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), device) {
+				continue
+			}
+			if _, err := os.Stat(fs.sys.Path(sysBlockPath, device, entry.Name(), sysBlockPartition)); err != nil {
+				continue
+			}
+			part, err := fs.readBlockDeviceNode(device, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			part.Parent = device
+			part.Removable = disk.Removable
+			disk.Partitions = append(disk.Partitions, part.DeviceName)
+			topology[part.DeviceName] = part
+		}
+		topology[device] = disk
+	}
+
+	return topology, nil
+}
+
+// readBlockDeviceNode reads the attributes of the device found at
+// /sys/block/<path...>. The last path element is the device name.
+func (fs FS) readBlockDeviceNode(path ...string) (BlockDeviceNode, error) {
+	name := path[len(path)-1]
+	dir := fs.sys.Path(append([]string{sysBlockPath}, path...)...)
+	node := BlockDeviceNode{Info: Info{DeviceName: name}}
+
+	dev, err := util.SysReadFile(filepath.Join(dir, sysBlockDev))
+	if err != nil {
+		return node, fmt.Errorf("failed to read dev for %s: %w", name, err)
+	}
+	node.MajorNumber, node.MinorNumber, err = parseDevNumber(dev)
+	if err != nil {
+		return node, fmt.Errorf("failed to parse dev for %s: %w", name, err)
+	}
+
+	sectors, err := util.ReadUintFromFile(filepath.Join(dir, sysBlockSize))
+	if err != nil {
+		return node, fmt.Errorf("failed to read size for %s: %w", name, err)
+	}
+	node.SizeBytes = sectors * procfs.SectorSize
+
+	ro, err := util.ReadUintFromFile(filepath.Join(dir, sysBlockRO))
+	if err != nil {
+		return node, fmt.Errorf("failed to read ro for %s: %w", name, err)
+	}
+	node.ReadOnly = ro == 1
+
+	// Partitions have no removable attribute but a partition number.
+	if len(path) == 1 {
+		removable, err := util.ReadUintFromFile(filepath.Join(dir, sysBlockRemovable))
+		if err != nil {
+			return node, fmt.Errorf("failed to read removable for %s: %w", name, err)
+		}
+		node.Removable = removable == 1
+	} else {
+		node.PartitionNumber, err = util.ReadUintFromFile(filepath.Join(dir, sysBlockPartition))
+		if err != nil {
+			return node, fmt.Errorf("failed to read partition for %s: %w", name, err)
+		}
+	}
+
+	dmName, err := util.SysReadFile(filepath.Join(dir, sysBlockDM, "name"))
+	if err != nil && !os.IsNotExist(err) {
+		return node, fmt.Errorf("failed to read dm/name for %s: %w", name, err)
+	}
+	node.DMName = dmName
+
+	if node.Holders, err = readDirNames(filepath.Join(dir, sysBlockHolders)); err != nil {
+		return node, fmt.Errorf("failed to read holders for %s: %w", name, err)
+	}
+	if node.Slaves, err = readDirNames(filepath.Join(dir, sysUnderlyingDev)); err != nil {
+		return node, fmt.Errorf("failed to read slaves for %s: %w", name, err)
+	}
+
+	return node, nil
+}
+
+// readDirNames returns the sorted entry names of a directory, or nil if the
+// directory does not exist.
+func readDirNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// parseDevNumber parses a "major:minor" device number string.
+func parseDevNumber(s string) (uint32, uint32, error) {
+	major, minor, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: invalid device number %q", procfs.ErrFileParse, s)
+	}
+	maj, err := strconv.ParseUint(major, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	mnr, err := strconv.ParseUint(minor, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint32(maj), uint32(mnr), nil
+}
+
+// DeviceByNumber returns the device with the given major and minor numbers,
+// as found for instance in procfs.MountInfo.MajorMinorVer.
+func (t BlockDeviceTopology) DeviceByNumber(major, minor uint32) (BlockDeviceNode, bool) {
+	for _, node := range t {
+		if node.MajorNumber == major && node.MinorNumber == minor {
+			return node, true
+		}
+	}
+	return BlockDeviceNode{}, false
+}
+
+// PhysicalDevices returns the sorted names of the whole disks the named
+// device is ultimately built from. Partitions are resolved to their parent
+// disk and stacked devices such as LVM, dm-crypt or md are followed through
+// their slaves. A disk without slaves is returned as its own physical device.
+func (t BlockDeviceTopology) PhysicalDevices(name string) []string {
+	var (
+		physical []string
+		visited  = make(map[string]bool)
+		walk     func(string)
+	)
+	walk = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		node, ok := t[name]
+		if !ok {
+			return
+		}
+		if node.IsPartition() {
+			walk(node.Parent)
+			return
+		}
+		if len(node.Slaves) == 0 {
+			physical = append(physical, name)
+			return
+		}
+		for _, slave := range node.Slaves {
+			walk(slave)
+		}
+	}
+	walk(name)
+	slices.Sort(physical)
+	return physical
+}
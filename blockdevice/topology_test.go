@@ -0,0 +1,90 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSysBlockDeviceTopology(t *testing.T) {
+	blockdevice, err := NewFS(procfsFixtures, sysfsFixtures)
+	if err != nil {
+		t.Fatalf("failed to access blockdevice fs: %v", err)
+	}
+
+	topology, err := blockdevice.SysBlockDeviceTopology()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 19, len(topology); want != got {
+		t.Fatalf(failMsgFormat, "Incorrect number of devices", want, got)
+	}
+
+	for name, want := range map[string]BlockDeviceNode{
+		"sda": {
+			Info:       Info{MajorNumber: 8, MinorNumber: 0, DeviceName: "sda"},
+			SizeBytes:  3750748848 * 512,
+			Partitions: []string{"sda1", "sda2"},
+			Holders:    []string{"dm-0"},
+		},
+		"sda2": {
+			Info:            Info{MajorNumber: 8, MinorNumber: 2, DeviceName: "sda2"},
+			Parent:          "sda",
+			PartitionNumber: 2,
+			SizeBytes:       3749722767 * 512,
+		},
+		"dm-0": {
+			Info:      Info{MajorNumber: 252, MinorNumber: 0, DeviceName: "dm-0"},
+			SizeBytes: 3749722767 * 512,
+			DMName:    "vg0--lv_root",
+			Slaves:    []string{"sda"},
+		},
+		"sdc": {
+			Info:      Info{MajorNumber: 8, MinorNumber: 32, DeviceName: "sdc"},
+			SizeBytes: 104857600 * 512,
+			ReadOnly:  true,
+			Holders:   []string{"dm-1"},
+		},
+	} {
+		if diff := cmp.Diff(want, topology[name]); diff != "" {
+			t.Errorf("unexpected BlockDeviceNode %s (-want +got):\n%s", name, diff)
+		}
+	}
+
+	node, ok := topology.DeviceByNumber(252, 1)
+	if !ok {
+		t.Fatal("device 252:1 not found")
+	}
+	if node.DMName != "mpathA" {
+		t.Errorf(failMsgFormat, "Incorrect device for 252:1", "mpathA", node.DMName)
+	}
+	if _, ok := topology.DeviceByNumber(1, 1); ok {
+		t.Error("unexpected device for 1:1")
+	}
+
+	for name, want := range map[string][]string{
+		"sda1":    {"sda"},
+		"dm-0":    {"sda"},
+		"dm-1":    {"sdb", "sdc"},
+		"sdd":     {"sdd"},
+		"missing": nil,
+	} {
+		if diff := cmp.Diff(want, topology.PhysicalDevices(name)); diff != "" {
+			t.Errorf("unexpected physical devices for %s (-want +got):\n%s", name, diff)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/prometheus/procfs"
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// ZramStats models the zram files that are located in the sysfs tree for each
+// zram block device and described in the kernel documentation:
+// https://www.kernel.org/doc/html/latest/admin-guide/blockdev/zram.html
+type ZramStats struct {
+	// DiskSize is the uncompressed size of the device in bytes.
+	DiskSize uint64
+	// CompAlgorithmList contains the list of available compression algorithms.
+	CompAlgorithmList []string
+	// CompAlgorithmCurrent is the compression algorithm in use.
+	CompAlgorithmCurrent string
+	// BackingDev is the path of the writeback backing device, empty if none is
+	// configured or writeback is not supported by the kernel.
+	BackingDev string
+	// MMStat contains the memory statistics from mm_stat.
+	MMStat ZramMMStat
+	// IOStat contains the IO statistics from io_stat.
+	IOStat ZramIOStat
+	// BDStat contains the backing device statistics from bd_stat. Optional,
+	// exists only if CONFIG_ZRAM_WRITEBACK is enabled.
+	BDStat *ZramBDStat
+}
+
+// ZramMMStat models /sys/block/zram<id>/mm_stat.
+type ZramMMStat struct {
+	// OrigDataSize is the uncompressed size of data stored in bytes.
+	OrigDataSize uint64
+	// ComprDataSize is the compressed size of data stored in bytes.
+	ComprDataSize uint64
+	// MemUsedTotal is the amount of memory allocated for this disk in bytes,
+	// including allocator fragmentation and metadata overhead.
+	MemUsedTotal uint64
+	// MemLimit is the maximum amount of memory zram can use to store the
+	// compressed data in bytes, 0 means no limit.
+	MemLimit uint64
+	// MemUsedMax is the maximum amount of memory zram has consumed to store
+	// the data in bytes.
+	MemUsedMax uint64
+	// SamePages is the number of same element filled pages written to this
+	// disk, no memory is allocated for them.
+	SamePages uint64
+	// PagesCompacted is the number of pages freed during compaction.
+	PagesCompacted uint64
+	// HugePages is the number of incompressible pages. Zero on kernels
+	// before 4.19.
+	HugePages uint64
+	// HugePagesSince is the number of incompressible pages since zram set
+	// up. Zero on kernels before 5.15.
+	HugePagesSince uint64
+}
+
+// ZramIOStat models /sys/block/zram<id>/io_stat.
+type ZramIOStat struct {
+	// FailedReads is the number of failed reads.
+	FailedReads uint64
+	// FailedWrites is the number of failed writes.
+	FailedWrites uint64
+	// InvalidIO is the number of non-page-size-aligned I/O requests.
+	InvalidIO uint64
+	// NotifyFree is the number of pages freed because of swap slot free
+	// notifications.
+	NotifyFree uint64
+}
+
+// ZramBDStat models /sys/block/zram<id>/bd_stat. All values are in units of
+// 4K pages.
+type ZramBDStat struct {
+	// Count is the size of data written in the backing device.
+	Count uint64
+	// Reads is the number of reads from the backing device.
+	Reads uint64
+	// Writes is the number of writes to the backing device.
+	Writes uint64
+}
+
+// SysBlockZramDevices lists the zram device names from /sys/block/zram<id>.
+func (fs FS) SysBlockZramDevices() ([]string, error) {
+	devices, err := fs.SysBlockDevices()
+	if err != nil {
+		return nil, err
+	}
+	zram := []string{}
+	for _, device := range devices {
+		if strings.HasPrefix(device, "zram") {
+			zram = append(zram, device)
+		}
+	}
+	return zram, nil
+}
+
+// SysBlockZramStats returns stats for /sys/block/zram<id> where zram<id> is a
+// device name.
+func (fs FS) SysBlockZramStats(device string) (ZramStats, error) {
+	stats := ZramStats{}
+
+	diskSize, err := util.ReadUintFromFile(fs.sys.Path(sysBlockPath, device, "disksize"))
+	if err != nil {
+		return ZramStats{}, err
+	}
+	stats.DiskSize = diskSize
+
+	algorithms, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, "comp_algorithm"))
+	if err != nil {
+		return ZramStats{}, err
+	}
+	for s := range strings.FieldsSeq(algorithms) {
+		if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+			s = s[1 : len(s)-1]
+			stats.CompAlgorithmCurrent = s
+		}
+		stats.CompAlgorithmList = append(stats.CompAlgorithmList, s)
+	}
+
+	mmStat, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, "mm_stat"))
+	if err != nil {
+		return ZramStats{}, err
+	}
+	if stats.MMStat, err = parseZramMMStat(mmStat); err != nil {
+		return ZramStats{}, err
+	}
+
+	ioStat, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, "io_stat"))
+	if err != nil {
+		return ZramStats{}, err
+	}
+	if stats.IOStat, err = parseZramIOStat(ioStat); err != nil {
+		return ZramStats{}, err
+	}
+
+	// optional
+	backingDev, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, "backing_dev"))
+	if err != nil && !os.IsNotExist(err) {
+		return ZramStats{}, err
+	}
+	if backingDev != "none" {
+		stats.BackingDev = backingDev
+	}
+
+	bdStat, err := util.SysReadFile(fs.sys.Path(sysBlockPath, device, "bd_stat"))
+	switch {
+	case err == nil:
+		s, err := parseZramBDStat(bdStat)
+		if err != nil {
+			return ZramStats{}, err
+		}
+		stats.BDStat = &s
+	case !os.IsNotExist(err):
+		return ZramStats{}, err
+	}
+
+	return stats, nil
+}
+
+// parseZramFields parses a line of space separated unsigned integers,
+// requiring at least minFields and storing at most len(values) of them.
+func parseZramFields(file, data string, minFields int, values ...*uint64) error {
+	fields := strings.Fields(data)
+	if len(fields) < minFields {
+		return fmt.Errorf("%w: %s: expected at least %d fields, found %d", procfs.ErrFileParse, file, minFields, len(fields))
+	}
+	parsed, err := util.ParseUint64s(fields)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", procfs.ErrFileParse, file, err)
+	}
+	for i, p := range values {
+		if i < len(parsed) {
+			*p = parsed[i]
+		}
+	}
+	return nil
+}
+
+func parseZramMMStat(data string) (ZramMMStat, error) {
+	s := ZramMMStat{}
+	err := parseZramFields("mm_stat", data, 7,
+		&s.OrigDataSize,
+		&s.ComprDataSize,
+		&s.MemUsedTotal,
+		&s.MemLimit,
+		&s.MemUsedMax,
+		&s.SamePages,
+		&s.PagesCompacted,
+		&s.HugePages,
+		&s.HugePagesSince,
+	)
+	return s, err
+}
+
+func parseZramIOStat(data string) (ZramIOStat, error) {
+	s := ZramIOStat{}
+	err := parseZramFields("io_stat", data, 4,
+		&s.FailedReads,
+		&s.FailedWrites,
+		&s.InvalidIO,
+		&s.NotifyFree,
+	)
+	return s, err
+}
+
+func parseZramBDStat(data string) (ZramBDStat, error) {
+	s := ZramBDStat{}
+	err := parseZramFields("bd_stat", data, 3,
+		&s.Count,
+		&s.Reads,
+		&s.Writes,
+	)
+	return s, err
+}
@@ -0,0 +1,85 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockdevice
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSysBlockZramStats(t *testing.T) {
+	blockdevice, err := NewFS(procfsFixtures, sysfsFixtures)
+	if err != nil {
+		t.Fatalf("failed to access blockdevice fs: %v", err)
+	}
+
+	devices, err := blockdevice.SysBlockZramDevices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"zram0"}, devices); diff != "" {
+		t.Fatalf("unexpected zram devices (-want +got):\n%s", diff)
+	}
+
+	stats, err := blockdevice.SysBlockZramStats(devices[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ZramStats{
+		DiskSize:             8589934592,
+		CompAlgorithmList:    []string{"lzo", "lzo-rle", "lz4", "lz4hc", "842", "zstd"},
+		CompAlgorithmCurrent: "zstd",
+		BackingDev:           "/dev/nvme0n1p3",
+		MMStat: ZramMMStat{
+			OrigDataSize:   1175539712,
+			ComprDataSize:  285212672,
+			MemUsedTotal:   296480768,
+			MemLimit:       0,
+			MemUsedMax:     312451072,
+			SamePages:      42193,
+			PagesCompacted: 0,
+			HugePages:      1375,
+			HugePagesSince: 1381,
+		},
+		IOStat: ZramIOStat{
+			NotifyFree: 3720,
+		},
+		BDStat: &ZramBDStat{
+			Count:  512,
+			Reads:  104,
+			Writes: 2048,
+		},
+	}
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Fatalf("unexpected ZramStats (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseZramMMStat(t *testing.T) {
+	// Kernels before 4.19 report neither huge_pages nor huge_pages_since.
+	stats, err := parseZramMMStat("4096 74 12288 0 12288 0 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ZramMMStat{OrigDataSize: 4096, ComprDataSize: 74, MemUsedTotal: 12288, MemUsedMax: 12288}
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Fatalf("unexpected ZramMMStat (-want +got):\n%s", diff)
+	}
+
+	if _, err := parseZramMMStat("4096 74 12288"); err == nil {
+		t.Error("expected error for truncated mm_stat, got nil")
+	}
+}
@@ -26,6 +26,24 @@ type Stats struct {
 	QuotaOverride  uint64
 	SectorSize     uint64
 	CommitStats    CommitStats
+
+	// DevInfo contains the per-device information from devinfo/<devid>,
+	// keyed by device ID. Empty on kernels before 5.9.
+	DevInfo map[string]*DevInfo
+	// ExclusiveOperation is the exclusive operation currently running on
+	// the filesystem, e.g. "balance", "balance paused", "device replace" or
+	// "none". Empty on kernels before 5.10.
+	// Scrubs are not exclusive operations and are not reported by sysfs.
+	ExclusiveOperation string
+	// BGReclaimThreshold is the usage percentage below which block groups
+	// are automatically reclaimed, 0 if disabled.
+	BGReclaimThreshold uint64
+
+	// Qgroups contains the quota groups of the filesystem, keyed by their
+	// sysfs name "<level>_<id>". Empty if quotas are not enabled.
+	Qgroups             map[string]*Qgroup
+	QgroupsEnabled      bool
+	QgroupsInconsistent bool
 }
 
 // Allocation contains allocation statistics for data, metadata and system data.
@@ -67,6 +85,56 @@ type Device struct {
 	Size uint64
 }
 
+// DevInfo contains information about a device of a Btrfs filesystem, keyed by
+// device ID rather than device name.
+type DevInfo struct {
+	InFSMetadata  bool
+	Missing       bool
+	ReplaceTarget bool
+	Writeable     bool
+	ErrorStats    ErrorStats
+}
+
+// ErrorStats contains the persistent per-device error counters, as shown by
+// `btrfs device stats`.
+type ErrorStats struct {
+	WriteErrs      uint64
+	ReadErrs       uint64
+	FlushErrs      uint64
+	CorruptionErrs uint64
+	GenerationErrs uint64
+}
+
+// Qgroup contains the usage and limits of a Btrfs quota group.
+// See Linux fs/btrfs/qgroup.h for more information.
+type Qgroup struct {
+	// Level and ID of the qgroup, level 0 qgroups have the ID of the
+	// subvolume they account for.
+	Level, ID uint64
+
+	// Usage in bytes
+	ReferencedBytes uint64
+	ExclusiveBytes  uint64
+
+	// Limits in bytes, only enforced if the matching LimitFlags are set.
+	MaxReferencedBytes uint64
+	MaxExclusiveBytes  uint64
+
+	// LimitFlags is the bitmask of BTRFS_QGROUP_LIMIT_* flags, see the
+	// QgroupLimit* constants.
+	LimitFlags uint64
+}
+
+// Btrfs qgroup limit flags.
+const (
+	QgroupLimitMaxReferenced  = 1 << 0
+	QgroupLimitMaxExclusive   = 1 << 1
+	QgroupLimitRsvReferenced  = 1 << 2
+	QgroupLimitRsvExclusive   = 1 << 3
+	QgroupLimitRefCompressed  = 1 << 4
+	QgroupLimitExclCompressed = 1 << 5
+)
+
 // Number of commits and various time related statistics.
 // See Linux fs/btrfs/sysfs.c with 6.x version.
 type CommitStats struct {
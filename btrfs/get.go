@@ -220,6 +220,120 @@ func (r *reader) readDeviceInfo(d string) map[string]*Device {
 	return info
 }
 
+// readDevInfo returns the devinfo information for all device IDs associated with this filesystem.
+func (r *reader) readDevInfo(d string) map[string]*DevInfo {
+	// devinfo does not exist before Linux 5.9.
+	if _, err := os.Stat(path.Join(r.path, d)); os.IsNotExist(err) {
+		return nil
+	}
+
+	ids := r.listFiles(d)
+	info := make(map[string]*DevInfo, len(ids))
+	for _, id := range ids {
+		p := path.Join(d, id)
+		info[id] = &DevInfo{
+			InFSMetadata:  r.readValue(path.Join(p, "in_fs_metadata")) == 1,
+			Missing:       r.readValue(path.Join(p, "missing")) == 1,
+			ReplaceTarget: r.readValue(path.Join(p, "replace_target")) == 1,
+			Writeable:     r.readValue(path.Join(p, "writeable")) == 1,
+			ErrorStats:    r.readErrorStats(path.Join(p, "error_stats")),
+		}
+	}
+
+	return info
+}
+
+// readQgroups returns the quota groups of this filesystem.
+func (r *reader) readQgroups(d string) map[string]*Qgroup {
+	// qgroups only exists while quotas are enabled.
+	if _, err := os.Stat(path.Join(r.path, d)); os.IsNotExist(err) {
+		return nil
+	}
+
+	names := r.listFiles(d)
+	qgroups := make(map[string]*Qgroup, len(names))
+	for _, n := range names {
+		// Skip files such as enabled, inconsistent or drop_subtree_threshold,
+		// only the <level>_<id> directories are quota groups.
+		level, id, ok := strings.Cut(n, "_")
+		if !ok {
+			continue
+		}
+		q := &Qgroup{}
+		var err error
+		if q.Level, err = strconv.ParseUint(level, 10, 64); err != nil {
+			continue
+		}
+		if q.ID, err = strconv.ParseUint(id, 10, 64); err != nil {
+			continue
+		}
+		if fi, err := os.Stat(path.Join(r.path, d, n)); err != nil || !fi.IsDir() {
+			continue
+		}
+
+		p := path.Join(d, n)
+		q.ReferencedBytes = r.readValue(path.Join(p, "referenced"))
+		q.ExclusiveBytes = r.readValue(path.Join(p, "exclusive"))
+		q.MaxReferencedBytes = r.readValue(path.Join(p, "max_referenced"))
+		q.MaxExclusiveBytes = r.readValue(path.Join(p, "max_exclusive"))
+		q.LimitFlags = r.readValue(path.Join(p, "limit_flags"))
+		qgroups[n] = q
+	}
+
+	return qgroups
+}
+
+// readErrorStats returns the error_stats information for a device.
+func (r *reader) readErrorStats(p string) ErrorStats {
+	stats := ErrorStats{}
+
+	f, err := os.Open(path.Join(r.path, p))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.err = err
+		}
+		return stats
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Fields(line)
+		// require  <key> <value>
+		if len(parts) != 2 {
+			r.err = fmt.Errorf("invalid error_stats line %q", line)
+			return stats
+		}
+
+		value, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			r.err = fmt.Errorf("error parsing error_stats line: %w", err)
+			return stats
+		}
+
+		switch parts[0] {
+		case "write_errs":
+			stats.WriteErrs = value
+		case "read_errs":
+			stats.ReadErrs = value
+		case "flush_errs":
+			stats.FlushErrs = value
+		case "corruption_errs":
+			stats.CorruptionErrs = value
+		case "generation_errs":
+			stats.GenerationErrs = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		r.err = fmt.Errorf("error scanning error_stats file: %w", err)
+	}
+
+	return stats
+}
+
 // readFilesystemStats reads Btrfs statistics for a filesystem.
 func (r *reader) readFilesystemStats() (s *Stats) {
 	// First get disk info, and add it to reader
@@ -250,6 +364,16 @@ func (r *reader) readFilesystemStats() (s *Stats) {
 
 		// Read commit stats data
 		CommitStats: r.readCommitStats("commit_stats"),
+
+		// Read device error counters and operation status
+		DevInfo:            r.readDevInfo("devinfo"),
+		ExclusiveOperation: r.readFile("exclusive_operation"),
+		BGReclaimThreshold: r.readValue("bg_reclaim_threshold"),
+
+		// Read quota groups
+		Qgroups:             r.readQgroups("qgroups"),
+		QgroupsEnabled:      r.readValue("qgroups/enabled") == 1,
+		QgroupsInconsistent: r.readValue("qgroups/inconsistent") == 1,
 	}
 	return
 }
@@ -13,7 +13,11 @@
 
 package btrfs
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
 
 type testVector struct {
 	uuid, label        string
@@ -126,3 +130,44 @@ func TestFSBtrfsStats(t *testing.T) {
 		}
 	}
 }
+
+func TestFSBtrfsDevInfo(t *testing.T) {
+	btrfs, err := NewFS("testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access Btrfs filesystem: %v", err)
+	}
+	stats, err := btrfs.Stats()
+	if err != nil {
+		t.Fatalf("failed to parse Btrfs stats: %v", err)
+	}
+
+	if want, got := "device replace", stats[0].ExclusiveOperation; want != got {
+		t.Errorf("unexpected exclusive operation:\nwant: %q\nhave: %q", want, got)
+	}
+	if want, got := uint64(75), stats[0].BGReclaimThreshold; want != got {
+		t.Errorf("unexpected bg_reclaim_threshold:\nwant: %d\nhave: %d", want, got)
+	}
+
+	want := map[string]*DevInfo{
+		"1": {InFSMetadata: true, Writeable: true},
+		"2": {
+			InFSMetadata: true,
+			Writeable:    true,
+			ErrorStats: ErrorStats{
+				WriteErrs:      4,
+				ReadErrs:       37,
+				FlushErrs:      1,
+				CorruptionErrs: 12,
+				GenerationErrs: 2,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, stats[0].DevInfo); diff != "" {
+		t.Errorf("unexpected devinfo (-want +got):\n%s", diff)
+	}
+
+	// The second fixture predates devinfo and exclusive_operation.
+	if stats[1].DevInfo != nil || stats[1].ExclusiveOperation != "" {
+		t.Errorf("unexpected devinfo for %q: %v", stats[1].UUID, stats[1].DevInfo)
+	}
+}
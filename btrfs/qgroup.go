@@ -0,0 +1,55 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btrfs
+
+import (
+	"path/filepath"
+
+	"github.com/prometheus/procfs"
+)
+
+// SubvolumePaths returns the paths of the mounted subvolumes of the filesystem
+// keyed by the name of their level 0 qgroup, e.g. "0_256". The mounts, as
+// returned by procfs.GetMounts, are matched to the filesystem by their source
+// device, after resolving symlinks such as /dev/mapper/<name>. Subvolumes
+// that are not mounted are not included.
+func (s *Stats) SubvolumePaths(mounts []*procfs.MountInfo) map[string]string {
+	paths := make(map[string]string)
+	for _, m := range mounts {
+		if m.FSType != "btrfs" {
+			continue
+		}
+		// Device-mapper devices are mounted by their /dev/mapper symlink,
+		// while Devices is keyed by kernel names like "dm-0".
+		source := m.Source
+		if resolved, err := filepath.EvalSymlinks(source); err == nil {
+			source = resolved
+		}
+		if _, ok := s.Devices[filepath.Base(source)]; !ok {
+			continue
+		}
+
+		id, ok := m.SuperOptions["subvolid"]
+		if !ok {
+			continue
+		}
+		path, ok := m.SuperOptions["subvol"]
+		if !ok {
+			path = m.Root
+		}
+		paths["0_"+id] = path
+	}
+
+	return paths
+}
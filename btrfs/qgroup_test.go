@@ -0,0 +1,138 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btrfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/prometheus/procfs"
+)
+
+func TestFSBtrfsQgroups(t *testing.T) {
+	btrfs, err := NewFS("testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access Btrfs filesystem: %v", err)
+	}
+	stats, err := btrfs.Stats()
+	if err != nil {
+		t.Fatalf("failed to parse Btrfs stats: %v", err)
+	}
+
+	if !stats[0].QgroupsEnabled || stats[0].QgroupsInconsistent {
+		t.Errorf("unexpected qgroup status: enabled %t, inconsistent %t", stats[0].QgroupsEnabled, stats[0].QgroupsInconsistent)
+	}
+
+	want := map[string]*Qgroup{
+		"0_5": {Level: 0, ID: 5, ReferencedBytes: 16384, ExclusiveBytes: 16384},
+		"0_256": {
+			Level:              0,
+			ID:                 256,
+			ReferencedBytes:    10737418240,
+			ExclusiveBytes:     9663676416,
+			MaxReferencedBytes: 21474836480,
+			LimitFlags:         QgroupLimitMaxReferenced,
+		},
+		"0_257": {
+			Level:             0,
+			ID:                257,
+			ReferencedBytes:   5368709120,
+			ExclusiveBytes:    5368709120,
+			MaxExclusiveBytes: 6442450944,
+			LimitFlags:        QgroupLimitMaxExclusive,
+		},
+		"1_100": {Level: 1, ID: 100, ReferencedBytes: 16106127360, ExclusiveBytes: 15032385536},
+	}
+	if diff := cmp.Diff(want, stats[0].Qgroups); diff != "" {
+		t.Errorf("unexpected qgroups (-want +got):\n%s", diff)
+	}
+
+	if stats[1].Qgroups != nil || stats[1].QgroupsEnabled {
+		t.Errorf("unexpected qgroups for %q: %v", stats[1].UUID, stats[1].Qgroups)
+	}
+}
+
+func TestSubvolumePaths(t *testing.T) {
+	stats := &Stats{Devices: map[string]*Device{"loop25": {}, "loop26": {}}}
+	mounts := []*procfs.MountInfo{
+		{
+			Root:         "/@home",
+			MountPoint:   "/home",
+			FSType:       "btrfs",
+			Source:       "/dev/loop26",
+			SuperOptions: map[string]string{"subvolid": "256", "subvol": "/@home"},
+		},
+		{
+			// The mount root is used when subvol is not reported.
+			Root:         "/@srv",
+			MountPoint:   "/srv/tenant",
+			FSType:       "btrfs",
+			Source:       "/dev/loop25",
+			SuperOptions: map[string]string{"subvolid": "257"},
+		},
+		{
+			Root:         "/",
+			MountPoint:   "/other",
+			FSType:       "btrfs",
+			Source:       "/dev/sdb",
+			SuperOptions: map[string]string{"subvolid": "5", "subvol": "/"},
+		},
+		{
+			Root:       "/",
+			MountPoint: "/",
+			FSType:     "ext4",
+			Source:     "/dev/loop25",
+		},
+	}
+
+	want := map[string]string{
+		"0_256": "/@home",
+		"0_257": "/@srv",
+	}
+	if diff := cmp.Diff(want, stats.SubvolumePaths(mounts)); diff != "" {
+		t.Errorf("unexpected subvolume paths (-want +got):\n%s", diff)
+	}
+}
+
+func TestSubvolumePathsDeviceMapper(t *testing.T) {
+	dev := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dev, "dm-0"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dev, "mapper"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../dm-0", filepath.Join(dev, "mapper", "vg-root")); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := &Stats{Devices: map[string]*Device{"dm-0": {}}}
+	mounts := []*procfs.MountInfo{
+		{
+			Root:         "/@",
+			MountPoint:   "/",
+			FSType:       "btrfs",
+			Source:       filepath.Join(dev, "mapper", "vg-root"),
+			SuperOptions: map[string]string{"subvolid": "256", "subvol": "/@"},
+		},
+	}
+
+	want := map[string]string{"0_256": "/@"}
+	if diff := cmp.Diff(want, stats.SubvolumePaths(mounts)); diff != "" {
+		t.Errorf("unexpected subvolume paths (-want +got):\n%s", diff)
+	}
+}
@@ -81,3 +81,48 @@ func parseBuddyInfo(r io.Reader) ([]BuddyInfo, error) {
 
 	return buddyInfo, scanner.Err()
 }
+
+// freeBlocks returns the number of free pages, the number of free blocks and
+// the number of free blocks large enough for an allocation of 2^order pages,
+// counted in units of 2^order pages.
+func (b BuddyInfo) freeBlocks(order int) (pages, blocks, suitable float64) {
+	for o, n := range b.Sizes {
+		pages += n * float64(uint64(1)<<o)
+		blocks += n
+		if o >= order {
+			suitable += n * float64(uint64(1)<<(o-order))
+		}
+	}
+	return pages, blocks, suitable
+}
+
+// FragmentationIndex returns the fragmentation index of the zone for an
+// allocation of 2^order pages, computed like the extfrag_index file in
+// /sys/kernel/debug/extfrag. Values towards 0 mean an allocation would fail
+// due to lack of memory, values towards 1 that it would fail due to external
+// fragmentation. -1 means the allocation would succeed.
+func (b BuddyInfo) FragmentationIndex(order int) float64 {
+	pages, blocks, suitable := b.freeBlocks(order)
+	if blocks == 0 {
+		return 0
+	}
+	if suitable > 0 {
+		return -1
+	}
+
+	requested := float64(uint64(1) << order)
+	return 1 - (1+pages/requested)/blocks
+}
+
+// UnusableIndex returns the fraction of free memory of the zone that is
+// unusable for an allocation of 2^order pages, computed like the
+// unusable_index file in /sys/kernel/debug/extfrag. A zone without free
+// memory is entirely unusable.
+func (b BuddyInfo) UnusableIndex(order int) float64 {
+	pages, _, suitable := b.freeBlocks(order)
+	if pages == 0 {
+		return 1
+	}
+
+	return (pages - suitable*float64(uint64(1)<<order)) / pages
+}
@@ -14,6 +14,7 @@
 package procfs
 
 import (
+	"math"
 	"strings"
 	"testing"
 )
@@ -72,3 +73,36 @@ Node 0, zone   Normal   4381   1093    185   1530    567    102      4      0
 		t.Fatalf("error parsing file: mismatch in number of buddyinfo buckets, previous count %q, new count %q", want, got)
 	}
 }
+
+func TestBuddyInfoFragmentation(t *testing.T) {
+	buddyInfo, err := getProcFixtures(t).BuddyInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dma32 := buddyInfo[1]
+
+	for _, test := range []struct {
+		order         int
+		fragmentation float64
+		unusable      float64
+	}{
+		{order: 0, fragmentation: -1, unusable: 0},
+		{order: 4, fragmentation: -1, unusable: 8867.0 / 14179.0},
+		{order: 9, fragmentation: 1 - (1+14179.0/512.0)/2848.0, unusable: 1},
+	} {
+		if want, got := test.fragmentation, dma32.FragmentationIndex(test.order); math.Abs(want-got) > 1e-9 {
+			t.Errorf("want fragmentation index %f for order %d, got %f", want, test.order, got)
+		}
+		if want, got := test.unusable, dma32.UnusableIndex(test.order); math.Abs(want-got) > 1e-9 {
+			t.Errorf("want unusable index %f for order %d, got %f", want, test.order, got)
+		}
+	}
+
+	empty := BuddyInfo{Sizes: make([]float64, 11)}
+	if got := empty.FragmentationIndex(3); got != 0 {
+		t.Errorf("want fragmentation index 0 for empty zone, got %f", got)
+	}
+	if got := empty.UnusableIndex(3); got != 1 {
+		t.Errorf("want unusable index 1 for empty zone, got %f", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ceph provides access to the RBD devices and the state of the Linux
+// kernel Ceph client.
+package ceph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/fs"
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// NoSnap is the snapshot ID of an RBD device mapping the head of an image.
+const NoSnap = ^uint64(0) - 1
+
+// FS represents the pseudo-filesystem sys, which provides an interface to
+// kernel data structures.
+type FS struct {
+	sys *fs.FS
+}
+
+// NewDefaultFS returns a new Ceph FS using the default sys fs mount point. It
+// will error if the mount point can't be read.
+func NewDefaultFS() (FS, error) {
+	return NewFS(fs.DefaultSysMountPoint)
+}
+
+// NewFS returns a new Ceph FS using the given sys fs mount point. It will
+// error if the mount point can't be read.
+func NewFS(mountPoint string) (FS, error) {
+	if strings.TrimSpace(mountPoint) == "" {
+		mountPoint = fs.DefaultSysMountPoint
+	}
+	sys, err := fs.NewFS(mountPoint)
+	if err != nil {
+		return FS{}, err
+	}
+	return FS{&sys}, nil
+}
+
+// RBDDevice contains the attributes of a mapped RBD device.
+// See Documentation/ABI/testing/sysfs-bus-rbd in the Linux kernel for more
+// information.
+type RBDDevice struct {
+	// ID is the device ID, e.g. "0" for /dev/rbd0.
+	ID     string
+	Pool   string
+	PoolID uint64
+	// Namespace is the RADOS namespace of the image. Empty on kernels before
+	// 4.19 and for images in the default namespace.
+	Namespace string
+	Name      string
+	ImageID   string
+	// SnapID is the ID of the mapped snapshot, NoSnap if the head of the
+	// image is mapped.
+	SnapID uint64
+	// CurrentSnap is the name of the mapped snapshot, "-" if the head of the
+	// image is mapped.
+	CurrentSnap string
+	// ClientID is the Ceph client the device is mapped with, e.g.
+	// "client4182".
+	ClientID    string
+	ClusterFSID string
+	// Features is the bitmask of RBD image features.
+	Features uint64
+	// Size is the size of the device in bytes.
+	Size  uint64
+	Major uint64
+	Minor uint64
+}
+
+// RBDDevices retrieves the attributes of all mapped RBD devices from
+// /sys/bus/rbd/devices.
+func (fs FS) RBDDevices() ([]RBDDevice, error) {
+	entries, err := os.ReadDir(fs.sys.Path("bus/rbd/devices"))
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]RBDDevice, 0, len(entries))
+	for _, entry := range entries {
+		device, err := fs.RBDDevice(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, *device)
+	}
+
+	return devices, nil
+}
+
+// RBDDevice retrieves the attributes of the RBD device with the given ID from
+// /sys/bus/rbd/devices/<id>.
+func (fs FS) RBDDevice(id string) (*RBDDevice, error) {
+	path := fs.sys.Path("bus/rbd/devices", id)
+	device := RBDDevice{ID: id}
+
+	for file, p := range map[string]*string{
+		"pool":         &device.Pool,
+		"name":         &device.Name,
+		"image_id":     &device.ImageID,
+		"current_snap": &device.CurrentSnap,
+		"client_id":    &device.ClientID,
+		"cluster_fsid": &device.ClusterFSID,
+	} {
+		val, err := util.SysReadFile(filepath.Join(path, file))
+		if err != nil {
+			return nil, err
+		}
+		*p = val
+	}
+
+	for file, p := range map[string]*uint64{
+		"pool_id": &device.PoolID,
+		"snap_id": &device.SnapID,
+		"size":    &device.Size,
+		"major":   &device.Major,
+		"minor":   &device.Minor,
+	} {
+		val, err := util.ReadUintFromFile(filepath.Join(path, file))
+		if err != nil {
+			return nil, err
+		}
+		*p = val
+	}
+
+	features, err := util.ReadHexFromFile(filepath.Join(path, "features"))
+	if err != nil {
+		return nil, err
+	}
+	device.Features = features
+
+	namespace, err := util.SysReadFile(filepath.Join(path, "pool_ns"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	device.Namespace = namespace
+
+	return &device, nil
+}
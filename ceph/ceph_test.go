@@ -0,0 +1,162 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ceph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRBDDevices(t *testing.T) {
+	ceph, err := NewFS("testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access sys fs: %v", err)
+	}
+	devices, err := ceph.RBDDevices()
+	if err != nil {
+		t.Fatalf("failed to read RBD devices: %v", err)
+	}
+
+	want := []RBDDevice{
+		{
+			ID:          "0",
+			Pool:        "rbd",
+			PoolID:      2,
+			Name:        "vm-disk-1",
+			ImageID:     "10276b8b4567",
+			SnapID:      NoSnap,
+			CurrentSnap: "-",
+			ClientID:    "client4182",
+			ClusterFSID: "3c1f2a9e-5b7d-4c8e-9f0a-1b2c3d4e5f60",
+			Features:    0x3d,
+			Size:        10737418240,
+			Major:       251,
+			Minor:       0,
+		},
+		{
+			ID:          "1",
+			Pool:        "rbd",
+			PoolID:      2,
+			Namespace:   "tenant-a",
+			Name:        "db-data",
+			ImageID:     "1a2b3c4d5e6f",
+			SnapID:      4,
+			CurrentSnap: "nightly",
+			ClientID:    "client4182",
+			ClusterFSID: "3c1f2a9e-5b7d-4c8e-9f0a-1b2c3d4e5f60",
+			Features:    0x1,
+			Size:        53687091200,
+			Major:       251,
+			Minor:       16,
+		},
+	}
+	if diff := cmp.Diff(want, devices); diff != "" {
+		t.Fatalf("unexpected RBD devices (-want +got):\n%s", diff)
+	}
+}
+
+func TestClients(t *testing.T) {
+	ceph, err := NewFS("testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access sys fs: %v", err)
+	}
+	clients, err := ceph.Clients()
+	if err != nil {
+		t.Fatalf("failed to read Ceph clients: %v", err)
+	}
+
+	want := []Client{
+		{
+			FSID:     "3c1f2a9e-5b7d-4c8e-9f0a-1b2c3d4e5f60",
+			ClientID: "client4182",
+			OSDRequests: []OSDRequest{
+				{
+					TID:      1187,
+					OSD:      3,
+					PGID:     "2.1f",
+					Epoch:    1234,
+					Object:   "rbd_data.10276b8b4567.0000000000000a3f",
+					Attempts: 1,
+					Ops:      []string{"write"},
+				},
+				{
+					TID:      1190,
+					OSD:      -1,
+					PGID:     "2.3a",
+					Epoch:    1234,
+					Object:   "tenant-a/rbd_data.1a2b3c4d5e6f.0000000000000001",
+					Attempts: 3,
+					Ops:      []string{"read", "stat"},
+				},
+			},
+			MonClient: MonClient{
+				Have:        map[string]uint64{"monmap": 3, "osdmap": 1234},
+				FSClusterID: -1,
+				Requests:    []MonRequest{{TID: 42, Op: "statfs"}},
+			},
+		},
+		{
+			FSID:        "8e2f0d9a-1c3b-4d5e-8f7a-6b5c4d3e2f10",
+			ClientID:    "client9001",
+			OSDRequests: []OSDRequest{},
+			MonClient: MonClient{
+				Have:        map[string]uint64{"monmap": 1, "osdmap": 877, "fsmap": 12},
+				FSClusterID: 0,
+			},
+			MDSRequests: []MDSRequest{
+				{TID: 12, MDS: 0, Op: "create", Unsafe: true, Args: []string{"#10000000000/file.txt"}},
+				{TID: 13, MDS: -1, Op: "getattr", Args: []string{"#10000000001"}},
+			},
+			Caps: &Caps{Total: 1025, Avail: 1022, Used: 3, Min: 1024, Waiters: 1},
+		},
+	}
+	if diff := cmp.Diff(want, clients); diff != "" {
+		t.Fatalf("unexpected Ceph clients (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := parseOSDRequests(strings.NewReader("REQUESTS\n1187\tosd3\t2.1f\n")); err == nil {
+		t.Error("expected an error parsing a truncated osdc request")
+	}
+	if _, err := parseMonClient(strings.NewReader("have osdmap x\n")); err == nil {
+		t.Error("expected an error parsing an invalid monc epoch")
+	}
+	if _, err := parseMonClient(strings.NewReader("fs_cluster_id x\n")); err == nil {
+		t.Error("expected an error parsing an invalid monc fs_cluster_id")
+	}
+	if _, err := parseMDSRequests(strings.NewReader("x\tmds0\tcreate\n")); err == nil {
+		t.Error("expected an error parsing an invalid mdsc tid")
+	}
+	if _, err := parseCaps(strings.NewReader("total\t\tx\n")); err == nil {
+		t.Error("expected an error parsing invalid caps")
+	}
+}
+
+func TestParseMonClientUnknownLines(t *testing.T) {
+	monc, err := parseMonClient(strings.NewReader("have osdmap 5\nauth_state something\n7\tmon_get_version\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MonClient{
+		Have:        map[string]uint64{"osdmap": 5},
+		FSClusterID: -1,
+		Requests:    []MonRequest{{TID: 7, Op: "mon_get_version"}},
+	}
+	if diff := cmp.Diff(want, monc); diff != "" {
+		t.Fatalf("unexpected monc (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ceph
+
+// The formats parsed here are those of net/ceph/debugfs.c and
+// fs/ceph/debugfs.c in the Linux kernel.
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// Client contains the state of a kernel Ceph client instance, parsed from
+// /sys/kernel/debug/ceph/<fsid>.client<id>. Reading it requires debugfs to
+// be mounted and root privileges.
+type Client struct {
+	// FSID is the FSID of the cluster the client is connected to.
+	FSID string
+	// ClientID is the global ID of the client, e.g. "client4182".
+	ClientID string
+	// OSDRequests are the requests in flight to OSDs.
+	OSDRequests []OSDRequest
+	// MonClient contains the state of the monitor client.
+	MonClient MonClient
+	// MDSRequests are the requests in flight to MDSs, nil if the client is
+	// not used by a CephFS mount.
+	MDSRequests []MDSRequest
+	// Caps contains the capability usage, nil if the client is not used by a
+	// CephFS mount.
+	Caps *Caps
+}
+
+// OSDRequest is a request in flight to an OSD, parsed from a line of the
+// REQUESTS section of the osdc file.
+type OSDRequest struct {
+	TID uint64
+	// OSD is the ID of the target OSD, -1 if the request has no target, e.g.
+	// because the OSD is down.
+	OSD int64
+	// PGID is the placement group of the object, e.g. "2.1f".
+	PGID string
+	// Epoch is the OSD map epoch the request was targeted with.
+	Epoch uint64
+	// Object is the name of the object, prefixed by its namespace if any.
+	Object string
+	// Attempts is the number of times the request was sent.
+	Attempts uint64
+	// Ops are the names of the operations of the request, e.g. "write".
+	Ops []string
+}
+
+// MonClient contains the state of the monitor client, parsed from the monc
+// file.
+type MonClient struct {
+	// Have maps the names of the subscribed maps, e.g. "osdmap", to the
+	// epoch of the latest map received.
+	Have map[string]uint64
+	// FSClusterID is the ID of the CephFS file system the client is bound
+	// to, -1 if none.
+	FSClusterID int64
+	// Requests are the generic requests in flight to the monitors.
+	Requests []MonRequest
+}
+
+// MonRequest is a generic request in flight to a monitor.
+type MonRequest struct {
+	TID uint64
+	// Op is the type of the request, e.g. "statfs" or "mon_get_version".
+	Op string
+}
+
+// MDSRequest is a request in flight to an MDS, parsed from a line of the
+// mdsc file.
+type MDSRequest struct {
+	TID uint64
+	// MDS is the rank of the target MDS, -1 if the request has no session.
+	MDS int64
+	// Op is the name of the operation, e.g. "lookup" or "create".
+	Op string
+	// Unsafe indicates an unsafe reply was received and the request awaits
+	// the safe reply.
+	Unsafe bool
+	// Args are the remaining arguments of the request, e.g. inodes and
+	// paths.
+	Args []string
+}
+
+// Caps contains the capability usage of a CephFS client, parsed from the
+// caps file.
+type Caps struct {
+	Total    uint64
+	Avail    uint64
+	Used     uint64
+	Reserved uint64
+	Min      uint64
+	// Waiters is the number of tasks waiting for capabilities.
+	Waiters uint64
+}
+
+// Clients retrieves the state of all kernel Ceph client instances from
+// /sys/kernel/debug/ceph.
+func (fs FS) Clients() ([]Client, error) {
+	entries, err := os.ReadDir(fs.sys.Path("kernel/debug/ceph"))
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]Client, 0, len(entries))
+	for _, entry := range entries {
+		client, err := fs.client(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, *client)
+	}
+
+	return clients, nil
+}
+
+func (fs FS) client(name string) (*Client, error) {
+	fsid, clientID, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, fmt.Errorf("invalid ceph debugfs directory %q", name)
+	}
+	client := Client{FSID: fsid, ClientID: clientID}
+	path := fs.sys.Path("kernel/debug/ceph", name)
+
+	b, err := util.ReadFileNoStat(filepath.Join(path, "osdc"))
+	if err != nil {
+		return nil, err
+	}
+	if client.OSDRequests, err = parseOSDRequests(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	b, err = util.ReadFileNoStat(filepath.Join(path, "monc"))
+	if err != nil {
+		return nil, err
+	}
+	if client.MonClient, err = parseMonClient(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	// The mdsc and caps files only exist for clients of CephFS mounts.
+	b, err = util.ReadFileNoStat(filepath.Join(path, "mdsc"))
+	switch {
+	case err == nil:
+		if client.MDSRequests, err = parseMDSRequests(bytes.NewReader(b)); err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	b, err = util.ReadFileNoStat(filepath.Join(path, "caps"))
+	switch {
+	case err == nil:
+		if client.Caps, err = parseCaps(bytes.NewReader(b)); err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// parseOSDRequests parses the REQUESTS section of an osdc file. Lines look
+// like:
+//
+//	1187	osd3	2.1f	2.1fs0	[3,1,5]/3	[3,1,5]/3	e1234	rbd_data.10276b8b4567.0000000000000a3f	0x400024	1	write
+func parseOSDRequests(r io.Reader) ([]OSDRequest, error) {
+	requests := []OSDRequest{}
+	inRequests := false
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "REQUESTS"):
+			inRequests = true
+			continue
+		case strings.HasPrefix(line, "LINGER REQUESTS"), strings.HasPrefix(line, "BACKOFFS"):
+			inRequests = false
+			continue
+		case !inRequests || line == "" || strings.HasPrefix(line, "osd"):
+			// The requests of each OSD are preceded by a line describing
+			// the OSD session.
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		epoch := -1
+		for i := 3; i < len(fields); i++ {
+			if len(fields[i]) > 1 && fields[i][0] == 'e' && isDigits(fields[i][1:]) {
+				epoch = i
+				break
+			}
+		}
+		// The epoch is followed by the object, flags, attempts and ops.
+		if epoch < 0 || len(fields) < epoch+5 || !strings.HasPrefix(fields[1], "osd") {
+			return nil, fmt.Errorf("invalid osdc request line %q", line)
+		}
+
+		var (
+			req OSDRequest
+			err error
+		)
+		if req.TID, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid osdc request line %q: %w", line, err)
+		}
+		if req.OSD, err = strconv.ParseInt(strings.TrimPrefix(fields[1], "osd"), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid osdc request line %q: %w", line, err)
+		}
+		if req.Epoch, err = strconv.ParseUint(fields[epoch][1:], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid osdc request line %q: %w", line, err)
+		}
+		if req.Attempts, err = strconv.ParseUint(fields[len(fields)-2], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid osdc request line %q: %w", line, err)
+		}
+		req.PGID = fields[2]
+		req.Object = fields[epoch+1]
+		req.Ops = strings.Split(fields[len(fields)-1], ",")
+		requests = append(requests, req)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// parseMonClient parses a monc file.
+func parseMonClient(r io.Reader) (MonClient, error) {
+	monc := MonClient{Have: make(map[string]uint64), FSClusterID: -1}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// have <map> <epoch> [want <epoch>[+]]
+		if fields[0] == "have" {
+			if len(fields) < 3 {
+				return MonClient{}, fmt.Errorf("invalid monc line %q", line)
+			}
+			epoch, err := strconv.ParseUint(fields[2], 10, 64)
+			if err != nil {
+				return MonClient{}, fmt.Errorf("invalid monc line %q: %w", line, err)
+			}
+			monc.Have[fields[1]] = epoch
+			continue
+		}
+
+		// fs_cluster_id <id>
+		if fields[0] == "fs_cluster_id" {
+			if len(fields) != 2 {
+				return MonClient{}, fmt.Errorf("invalid monc line %q", line)
+			}
+			id, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return MonClient{}, fmt.Errorf("invalid monc line %q: %w", line, err)
+			}
+			monc.FSClusterID = id
+			continue
+		}
+
+		// <tid> <op>, other lines are skipped.
+		tid, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil || len(fields) < 2 {
+			continue
+		}
+		monc.Requests = append(monc.Requests, MonRequest{TID: tid, Op: fields[1]})
+	}
+
+	if err := s.Err(); err != nil {
+		return MonClient{}, err
+	}
+
+	return monc, nil
+}
+
+// parseMDSRequests parses an mdsc file. Lines look like:
+//
+//	12	mds0	create	(unsafe)	#10000000000/file.txt
+func parseMDSRequests(r io.Reader) ([]MDSRequest, error) {
+	requests := []MDSRequest{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid mdsc line %q", line)
+		}
+
+		tid, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mdsc line %q: %w", line, err)
+		}
+		req := MDSRequest{TID: tid, MDS: -1, Op: fields[2]}
+		if strings.HasPrefix(fields[1], "mds") {
+			if req.MDS, err = strconv.ParseInt(strings.TrimPrefix(fields[1], "mds"), 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid mdsc line %q: %w", line, err)
+			}
+		}
+		for _, f := range fields[3:] {
+			switch f {
+			case "":
+			case "(unsafe)":
+				req.Unsafe = true
+			default:
+				req.Args = append(req.Args, f)
+			}
+		}
+		requests = append(requests, req)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// parseCaps parses a caps file.
+func parseCaps(r io.Reader) (*Caps, error) {
+	var (
+		caps    Caps
+		waiters bool
+	)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0 || strings.HasPrefix(line, "--"):
+			continue
+		case line == "Waiters:":
+			waiters = true
+			continue
+		case waiters:
+			// Each waiter is listed by the tgid of the waiting process,
+			// below a header.
+			if _, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+				caps.Waiters++
+			}
+			continue
+		}
+
+		var p *uint64
+		switch fields[0] {
+		case "total":
+			p = &caps.Total
+		case "avail":
+			p = &caps.Avail
+		case "used":
+			p = &caps.Used
+		case "reserved":
+			p = &caps.Reserved
+		case "min":
+			p = &caps.Min
+		default:
+			// Header and per-inode capabilities.
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid caps line %q", line)
+		}
+		val, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid caps line %q: %w", line, err)
+		}
+		*p = val
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return &caps, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
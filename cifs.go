@@ -0,0 +1,541 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+// While implementing parsing of /proc/fs/cifs, fs/smb/client/cifs_debug.c and
+// fs/smb/client/smb2ops.c of the Linux kernel were used as a reference.
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+var cifsCommandLine = regexp.MustCompile(`^(\w+): (\d+) (?:total|sent) (\d+) failed$`)
+
+// A CIFSStats contains the statistics of the CIFS/SMB client, parsed from
+// /proc/fs/cifs/Stats.
+type CIFSStats struct {
+	// Number of sessions with servers.
+	Sessions uint64
+	// Number of unique shares mounted.
+	Shares uint64
+	// Number of large request/response buffers in use and the size of their
+	// pool.
+	Buffers        uint64
+	BufferPoolSize uint64
+	// Number of small request/response buffers in use and the size of their
+	// pool.
+	SmallBuffers        uint64
+	SmallBufferPoolSize uint64
+	// Number of requests (MIDs) in use.
+	Operations uint64
+	// Number of session and share reconnects.
+	SessionReconnects uint64
+	ShareReconnects   uint64
+	// Number of VFS operations in progress and the maximum number of VFS
+	// operations in progress at one time.
+	VFSOperations    uint64
+	MaxVFSOperations uint64
+	// Statistics broken down by share.
+	ShareStats []CIFSShareStats
+}
+
+// A CIFSShareStats contains the statistics of a single mounted share.
+type CIFSShareStats struct {
+	// The UNC name of the share, e.g. \\server\share.
+	Share string
+	// Whether the share needs to be reconnected.
+	Disconnected bool
+	// Number of SMBs sent for the share.
+	SMBs uint64
+	// Number of bytes read from and written to the share.
+	BytesRead    uint64
+	BytesWritten uint64
+	// Number of files opened locally and on the server.
+	OpenFiles         uint64
+	OpenFilesOnServer uint64
+	// Statistics broken down by SMB2+ command, e.g. "Creates", "Reads" or
+	// "OplockBreaks".
+	Commands map[string]CIFSCommandStats
+}
+
+// A CIFSCommandStats contains the number of times an SMB command was sent
+// and how many of those failed.
+type CIFSCommandStats struct {
+	Total  uint64
+	Failed uint64
+}
+
+// A CIFSDebugData contains the state of the CIFS/SMB client, parsed from
+// /proc/fs/cifs/DebugData.
+type CIFSDebugData struct {
+	// The version of the CIFS module.
+	Version string
+	// The features the CIFS module was built with.
+	Features []string
+	// The maximum buffer size, in bytes.
+	MaxBufSize uint64
+	// Number of VFS requests in progress.
+	ActiveVFSRequests uint64
+	// The connections to servers.
+	Servers []CIFSServer
+}
+
+// A CIFSServer contains the state of a connection to an SMB server.
+type CIFSServer struct {
+	ConnectionID string
+	Hostname     string
+	ClientGUID   string
+	// Number of credits granted by the server.
+	Credits uint64
+	// The negotiated SMB dialect, e.g. "0x311".
+	Dialect string
+	// The capabilities advertised by the server.
+	Capabilities uint64
+	TCPStatus    uint64
+	// Number of local users of the connection.
+	LocalUsers uint64
+	// Number of requests in flight, i.e. sent and waiting for a reply.
+	RequestsOnWire uint64
+	// Number of requests being sent and waiting for credits.
+	InSend       uint64
+	InMaxReqWait uint64
+	Sessions     []CIFSSession
+}
+
+// A CIFSSession contains the state of an SMB session.
+type CIFSSession struct {
+	// The address of the server, reported as "Name" by older kernels.
+	Address      string
+	Domain       string
+	Uses         uint64
+	Capability   uint64
+	Status       uint64
+	SecurityType string
+	SessionID    string
+	Shares       []CIFSShare
+}
+
+// A CIFSShare contains the state of a tree connection to an SMB share.
+type CIFSShare struct {
+	// The UNC name of the share, e.g. \\server\share.
+	Name string
+	// Whether the share is the IPC$ share of the session.
+	IPC    bool
+	Mounts uint64
+	Status uint64
+	Type   string
+	TreeID string
+}
+
+// CIFSStats returns the statistics of the CIFS/SMB client from
+// /proc/fs/cifs/Stats.
+func (fs FS) CIFSStats() (*CIFSStats, error) {
+	b, err := util.ReadFileNoStat(fs.proc.Path("fs/cifs/Stats"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCIFSStats(bytes.NewReader(b))
+}
+
+// CIFSDebugData returns the state of the CIFS/SMB client from
+// /proc/fs/cifs/DebugData.
+func (fs FS) CIFSDebugData() (*CIFSDebugData, error) {
+	b, err := util.ReadFileNoStat(fs.proc.Path("fs/cifs/DebugData"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCIFSDebugData(bytes.NewReader(b))
+}
+
+// parseCIFSStats parses a /proc/fs/cifs/Stats file.
+func parseCIFSStats(r io.Reader) (*CIFSStats, error) {
+	var (
+		stats CIFSStats
+		share *CIFSShareStats
+	)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+
+		// Shares are listed as "<n>) \\server\share", optionally followed by
+		// "DISCONNECTED".
+		if n, rest, ok := strings.Cut(line, ") "); ok && isDigits(n) {
+			fields := strings.Fields(rest)
+			stats.ShareStats = append(stats.ShareStats, CIFSShareStats{
+				Share:        fields[0],
+				Disconnected: len(fields) > 1 && fields[1] == "DISCONNECTED",
+				Commands:     make(map[string]CIFSCommandStats),
+			})
+			share = &stats.ShareStats[len(stats.ShareStats)-1]
+			continue
+		}
+
+		var err error
+		if share == nil {
+			err = parseCIFSStatsGlobal(&stats, line)
+		} else {
+			err = parseCIFSStatsShare(share, line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: CIFS Stats line %q: %w", ErrFileParse, line, err)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// parseCIFSStatsGlobal parses a line of the global section of
+// /proc/fs/cifs/Stats. Lines which are not understood are ignored.
+func parseCIFSStatsGlobal(stats *CIFSStats, line string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(line, "CIFS Session: "):
+		stats.Sessions, err = cifsUint(line, "CIFS Session: ")
+	case strings.HasPrefix(line, "Share (unique mount targets): "):
+		stats.Shares, err = cifsUint(line, "Share (unique mount targets): ")
+	case strings.HasPrefix(line, "SMB Request/Response Buffer: "):
+		err = cifsUints(line, map[string]*uint64{
+			"SMB Request/Response Buffer: ": &stats.Buffers,
+			"Pool size: ":                   &stats.BufferPoolSize,
+		})
+	case strings.HasPrefix(line, "SMB Small Req/Resp Buffer: "):
+		err = cifsUints(line, map[string]*uint64{
+			"SMB Small Req/Resp Buffer: ": &stats.SmallBuffers,
+			"Pool size: ":                 &stats.SmallBufferPoolSize,
+		})
+	case strings.HasPrefix(line, "Operations (MIDs): "):
+		stats.Operations, err = cifsUint(line, "Operations (MIDs): ")
+	case strings.HasSuffix(line, " share reconnects"):
+		_, err = fmt.Sscanf(line, "%d session %d share reconnects", &stats.SessionReconnects, &stats.ShareReconnects)
+	case strings.HasPrefix(line, "Total vfs operations: "):
+		err = cifsUints(line, map[string]*uint64{
+			"Total vfs operations: ": &stats.VFSOperations,
+			"maximum at one time: ":  &stats.MaxVFSOperations,
+		})
+	}
+	return err
+}
+
+// parseCIFSStatsShare parses a line of the statistics of a share in
+// /proc/fs/cifs/Stats. Lines which are not understood, e.g. the statistics
+// of SMB1 shares, are ignored.
+func parseCIFSStatsShare(share *CIFSShareStats, line string) error {
+	if m := cifsCommandLine.FindStringSubmatch(line); m != nil {
+		values, err := util.ParseUint64s(m[2:])
+		if err != nil {
+			return err
+		}
+		share.Commands[m[1]] = CIFSCommandStats{Total: values[0], Failed: values[1]}
+		return nil
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(line, "SMBs: "):
+		share.SMBs, err = cifsUint(line, "SMBs: ")
+	case strings.HasPrefix(line, "Bytes read: "):
+		err = cifsUints(line, map[string]*uint64{
+			"Bytes read: ":    &share.BytesRead,
+			"Bytes written: ": &share.BytesWritten,
+		})
+	case strings.HasPrefix(line, "Open files: "):
+		_, err = fmt.Sscanf(line, "Open files: %d total (local), %d open on server", &share.OpenFiles, &share.OpenFilesOnServer)
+	}
+	return err
+}
+
+// parseCIFSDebugData parses a /proc/fs/cifs/DebugData file.
+func parseCIFSDebugData(r io.Reader) (*CIFSDebugData, error) {
+	const (
+		sectionServer = iota
+		sectionSessions
+		sectionShares
+		sectionOther
+	)
+
+	var (
+		data    CIFSDebugData
+		server  *CIFSServer
+		session *CIFSSession
+		share   *CIFSShare
+		section int
+	)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(line, "CIFS Version "):
+			data.Version = strings.TrimPrefix(line, "CIFS Version ")
+		case strings.HasPrefix(line, "Features: "):
+			data.Features = strings.Split(strings.TrimPrefix(line, "Features: "), ",")
+		case strings.HasPrefix(line, "CIFSMaxBufSize: "):
+			data.MaxBufSize, err = cifsUint(line, "CIFSMaxBufSize: ")
+		case strings.HasPrefix(line, "Active VFS Requests: "):
+			data.ActiveVFSRequests, err = cifsUint(line, "Active VFS Requests: ")
+		case strings.Contains(line, "ConnectionId: ") && !strings.HasPrefix(line, "Channel: "):
+			data.Servers = append(data.Servers, CIFSServer{
+				ConnectionID: cifsValue(line, "ConnectionId: "),
+				Hostname:     cifsValue(line, "Hostname: "),
+			})
+			server, session, share = &data.Servers[len(data.Servers)-1], nil, nil
+			section = sectionServer
+		case server == nil:
+			// Global information which is not exposed.
+		case cifsSessionStart(line):
+			// Each session starts with its index, only the first one is
+			// preceded by "Sessions:".
+			server.Sessions = append(server.Sessions, CIFSSession{})
+			session, share = &server.Sessions[len(server.Sessions)-1], nil
+			section = sectionSessions
+			err = parseCIFSSessionLine(session, line)
+		case line == "Sessions:":
+			section = sectionSessions
+		case line == "Shares:":
+			section = sectionShares
+		case strings.HasSuffix(line, ":") || strings.HasPrefix(line, "Server interfaces: "):
+			// Other sections, e.g. "MIDs:" or "Server interfaces: 1".
+			section = sectionOther
+		case section == sectionServer:
+			err = parseCIFSServerLine(server, line)
+		case section == sectionSessions:
+			if session != nil {
+				err = parseCIFSSessionLine(session, line)
+			}
+		case section == sectionShares:
+			if session == nil {
+				// Older kernels do not list the shares within a session.
+				server.Sessions = append(server.Sessions, CIFSSession{})
+				session = &server.Sessions[len(server.Sessions)-1]
+			}
+			if rest, ok := cifsIndexed(line); ok {
+				name := strings.TrimPrefix(rest, "IPC: ")
+				session.Shares = append(session.Shares, CIFSShare{
+					Name: strings.Fields(name)[0],
+					IPC:  strings.HasPrefix(rest, "IPC: "),
+				})
+				share = &session.Shares[len(session.Shares)-1]
+			}
+			if share != nil {
+				err = parseCIFSShareLine(share, line)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: CIFS DebugData line %q: %w", ErrFileParse, line, err)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func parseCIFSServerLine(server *CIFSServer, line string) error {
+	if v := cifsValue(line, "ClientGUID: "); v != "" {
+		server.ClientGUID = v
+	}
+	if v := cifsValue(line, "Number of credits: "); v != "" {
+		// Newer kernels report the credits for regular, echo and oplock
+		// requests, e.g. "8190,1,1".
+		credits, err := strconv.ParseUint(strings.Split(v, ",")[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		server.Credits = credits
+	}
+	if v := cifsValue(line, "Dialect "); v != "" {
+		server.Dialect = v
+	}
+	if v := cifsValue(line, "Server capabilities: "); v != "" {
+		capabilities, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return err
+		}
+		server.Capabilities = capabilities
+	}
+	return cifsUints(line, map[string]*uint64{
+		"TCP status: ":            &server.TCPStatus,
+		"Local Users To Server: ": &server.LocalUsers,
+		"Req On Wire: ":           &server.RequestsOnWire,
+		"In Send: ":               &server.InSend,
+		"In MaxReq Wait: ":        &server.InMaxReqWait,
+	})
+}
+
+func parseCIFSSessionLine(session *CIFSSession, line string) error {
+	if v := cifsValue(line, "Address: "); v != "" {
+		session.Address = v
+	}
+	if v := cifsValue(line, "Name: "); v != "" {
+		session.Address = v
+	}
+	if v := cifsValue(line, "Domain: "); v != "" {
+		session.Domain = v
+	}
+	if v := cifsValue(line, "Security type: "); v != "" {
+		session.SecurityType = v
+	}
+	if v := cifsValue(line, "SessionId: "); v != "" {
+		session.SessionID = v
+	}
+	if v := cifsValue(line, "Capability: "); v != "" {
+		capability, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return err
+		}
+		session.Capability = capability
+	}
+	return cifsUints(line, map[string]*uint64{
+		"Uses: ":           &session.Uses,
+		"Session Status: ": &session.Status,
+	})
+}
+
+func parseCIFSShareLine(share *CIFSShare, line string) error {
+	if v := cifsValue(line, "type: "); v != "" {
+		share.Type = v
+	}
+	if v := cifsValue(line, "tid: "); v != "" {
+		share.TreeID = v
+	}
+	return cifsUints(line, map[string]*uint64{
+		"Mounts: ": &share.Mounts,
+		"Status: ": &share.Status,
+	})
+}
+
+// cifsKeys are the keys of the fields parsed from Stats and DebugData lines.
+// A field starts with a known key at the start of the line or after white
+// space, so values containing other "Key: " strings do not split fields.
+var cifsKeys = []string{
+	// Stats
+	"CIFS Session: ", "Share (unique mount targets): ",
+	"SMB Request/Response Buffer: ", "SMB Small Req/Resp Buffer: ",
+	"Pool size: ", "Operations (MIDs): ", "Total vfs operations: ",
+	"maximum at one time: ", "SMBs: ", "Bytes read: ", "Bytes written: ",
+	// DebugData
+	"CIFSMaxBufSize: ", "Active VFS Requests: ",
+	"ConnectionId: ", "Hostname: ", "ClientGUID: ", "Number of credits: ",
+	"Dialect ", "Server capabilities: ", "TCP status: ",
+	"Local Users To Server: ", "Req On Wire: ", "In Send: ",
+	"In MaxReq Wait: ", "Address: ", "Name: ", "Domain: ", "Uses: ",
+	"Capability: ", "Session Status: ", "Security type: ", "SessionId: ",
+	"Mounts: ", "Status: ", "type: ", "tid: ",
+}
+
+// cifsValue returns the whitespace delimited value following key in line, or
+// an empty string if line does not contain key as a field.
+func cifsValue(line, key string) string {
+	for i := 0; i < len(line); i++ {
+		if i > 0 && line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+		// Prefer the longest key, e.g. "Security type: " over "type: ".
+		var match string
+		for _, k := range cifsKeys {
+			if len(k) > len(match) && strings.HasPrefix(line[i:], k) {
+				match = k
+			}
+		}
+		if match == "" {
+			continue
+		}
+		if match == key {
+			fields := strings.Fields(line[i+len(key):])
+			if len(fields) == 0 {
+				return ""
+			}
+			return fields[0]
+		}
+		i += len(match) - 1
+	}
+	return ""
+}
+
+// cifsIndexed returns the remainder of a line starting with an index, e.g.
+// "[1] ", as printed before each session and share.
+func cifsIndexed(line string) (string, bool) {
+	index, rest, ok := strings.Cut(line, "] ")
+	if !ok || !strings.HasPrefix(index, "[") || !isDigits(index[1:]) {
+		return "", false
+	}
+	return rest, true
+}
+
+// cifsSessionStart reports whether line starts a session. Sessions start with
+// an index followed by the first field of the session, e.g. "Address: " or
+// "Name: " depending on the kernel, while indexed shares start with their
+// UNC name.
+func cifsSessionStart(line string) bool {
+	rest, ok := cifsIndexed(line)
+	return ok && !strings.HasPrefix(rest, `\\`) && !strings.HasPrefix(rest, "IPC: ")
+}
+
+// cifsUint parses the integer value following key in line.
+func cifsUint(line, key string) (uint64, error) {
+	return strconv.ParseUint(cifsValue(line, key), 10, 64)
+}
+
+// cifsUints parses the integer values following each key found in line.
+func cifsUints(line string, values map[string]*uint64) error {
+	for key, p := range values {
+		v := cifsValue(line, key)
+		if v == "" {
+			continue
+		}
+		val, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		*p = val
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
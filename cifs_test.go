@@ -0,0 +1,234 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCIFSStats(t *testing.T) {
+	stats, err := getProcFixtures(t).CIFSStats()
+	if err != nil {
+		t.Fatalf("failed to parse CIFS stats: %v", err)
+	}
+
+	want := &CIFSStats{
+		Sessions:            1,
+		Shares:              2,
+		Buffers:             1,
+		BufferPoolSize:      5,
+		SmallBuffers:        1,
+		SmallBufferPoolSize: 30,
+		Operations:          0,
+		SessionReconnects:   0,
+		ShareReconnects:     1,
+		VFSOperations:       20,
+		MaxVFSOperations:    2,
+		ShareStats: []CIFSShareStats{
+			{
+				Share:             `\\filer1\share`,
+				SMBs:              42,
+				BytesRead:         1048576,
+				BytesWritten:      4096,
+				OpenFiles:         2,
+				OpenFilesOnServer: 2,
+				Commands: map[string]CIFSCommandStats{
+					"TreeConnects":     {Total: 1},
+					"TreeDisconnects":  {},
+					"Creates":          {Total: 10, Failed: 1},
+					"Closes":           {Total: 8},
+					"Flushes":          {},
+					"Reads":            {Total: 16},
+					"Writes":           {Total: 1},
+					"Locks":            {},
+					"IOCTLs":           {Total: 2, Failed: 1},
+					"QueryDirectories": {Total: 3},
+					"ChangeNotifies":   {},
+					"QueryInfos":       {Total: 12},
+					"SetInfos":         {},
+					"OplockBreaks":     {},
+				},
+			},
+			{
+				Share:        `\\filer1\archive`,
+				Disconnected: true,
+				SMBs:         3,
+				Commands: map[string]CIFSCommandStats{
+					"TreeConnects": {Total: 2, Failed: 1},
+					"Creates":      {},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Fatalf("unexpected CIFS stats (-want +got):\n%s", diff)
+	}
+}
+
+func TestCIFSStatsInvalid(t *testing.T) {
+	for _, s := range []string{
+		"CIFS Session: x\n",
+		"1) \\\\filer1\\share\nSMBs: -1\n",
+	} {
+		if _, err := parseCIFSStats(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+}
+
+func TestCIFSDebugData(t *testing.T) {
+	data, err := getProcFixtures(t).CIFSDebugData()
+	if err != nil {
+		t.Fatalf("failed to parse CIFS debug data: %v", err)
+	}
+
+	want := &CIFSDebugData{
+		Version:           "2.45",
+		Features:          []string{"DFS", "FSCACHE", "STATS2", "DEBUG", "ALLOW_INSECURE_LEGACY", "CIFS_POSIX", "UPCALL(SPNEGO)", "XATTR", "ACL", "WITNESS"},
+		MaxBufSize:        16384,
+		ActiveVFSRequests: 1,
+		Servers: []CIFSServer{
+			{
+				ConnectionID:   "0x1",
+				Hostname:       "filer1",
+				ClientGUID:     "8F6A9C2E-1B3D-4E5F-9A8B-7C6D5E4F3A2B",
+				Credits:        8190,
+				Dialect:        "0x311",
+				Capabilities:   0x300047,
+				TCPStatus:      1,
+				LocalUsers:     1,
+				RequestsOnWire: 2,
+				Sessions: []CIFSSession{
+					{
+						Address:      "192.168.1.10",
+						Uses:         1,
+						Capability:   0x300047,
+						Status:       1,
+						SecurityType: "RawNTLMSSP",
+						SessionID:    "0x5c8a1b2c3d4e5f60",
+						Shares: []CIFSShare{
+							{Name: `\\filer1\IPC$`, IPC: true, Mounts: 1, Status: 1, Type: "0", TreeID: "0x1"},
+							{Name: `\\filer1\share`, Mounts: 1, Status: 1, Type: "DISK", TreeID: "0x5"},
+						},
+					},
+					{
+						Address:      "192.168.1.10",
+						Uses:         2,
+						Capability:   0x300047,
+						Status:       1,
+						SecurityType: "Kerberos",
+						SessionID:    "0x5c8a1b2c3d4e5f61",
+						Shares: []CIFSShare{
+							{Name: `\\filer1\IPC$`, IPC: true, Mounts: 1, Status: 1, Type: "0", TreeID: "0x2"},
+							{Name: `\\filer1\home`, Mounts: 2, Status: 1, Type: "DISK", TreeID: "0x9"},
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, data); diff != "" {
+		t.Fatalf("unexpected CIFS debug data (-want +got):\n%s", diff)
+	}
+}
+
+func TestCIFSDebugDataNameSessions(t *testing.T) {
+	// Session lines of kernels which print the server address as "Name",
+	// followed by the channels of the connection.
+	content := `Servers:
+1) ConnectionId: 0x2 Hostname: filer2
+Number of credits: 512 Dialect 0x302
+
+	Sessions:
+	[1] Name: 10.0.0.5 Uses: 1 Domain: CORP Capability: 0x300067	Session Status: 1
+	Security type: RawNTLMSSP  SessionId: 0x1a
+	Shares:
+	[0] IPC: \\filer2\IPC$ Mounts: 1 DevInfo: 0x0 Attributes: 0x0
+	PathComponentMax: 0 Status: 1 type: 0 Serial Number: 0x0
+	tid: 0x1
+
+	[2] Name: 10.0.0.5 Uses: 1 Domain: LAB Capability: 0x300067	Session Status: 1
+	Security type: Kerberos  SessionId: 0x1b
+
+		Channel: 1 ConnectionId: 0x3 Number of credits: 512 Dialect 0x302
+	Shares:
+	[0] \\filer2\data Mounts: 1 DevInfo: 0x20 Attributes: 0x1006f
+	PathComponentMax: 255 Status: 1 type: DISK Serial Number: 0x1
+	tid: 0x5
+`
+	data, err := parseCIFSDebugData(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("failed to parse CIFS debug data: %v", err)
+	}
+
+	want := []CIFSSession{
+		{
+			Address:      "10.0.0.5",
+			Domain:       "CORP",
+			Uses:         1,
+			Capability:   0x300067,
+			Status:       1,
+			SecurityType: "RawNTLMSSP",
+			SessionID:    "0x1a",
+			Shares: []CIFSShare{
+				{Name: `\\filer2\IPC$`, IPC: true, Mounts: 1, Status: 1, Type: "0", TreeID: "0x1"},
+			},
+		},
+		{
+			Address:      "10.0.0.5",
+			Domain:       "LAB",
+			Uses:         1,
+			Capability:   0x300067,
+			Status:       1,
+			SecurityType: "Kerberos",
+			SessionID:    "0x1b",
+			Shares: []CIFSShare{
+				{Name: `\\filer2\data`, Mounts: 1, Status: 1, Type: "DISK", TreeID: "0x5"},
+			},
+		},
+	}
+	if len(data.Servers) != 1 {
+		t.Fatalf("want 1 server, got %d", len(data.Servers))
+	}
+	if diff := cmp.Diff(want, data.Servers[0].Sessions); diff != "" {
+		t.Fatalf("unexpected CIFS sessions (-want +got):\n%s", diff)
+	}
+}
+
+func TestCIFSValue(t *testing.T) {
+	tests := []struct {
+		line string
+		key  string
+		want string
+	}{
+		{line: "Security type: Kerberos  SessionId: 0x1", key: "type: ", want: ""},
+		{line: "Security type: Kerberos  SessionId: 0x1", key: "Security type: ", want: "Kerberos"},
+		{line: "Security type: Kerberos  SessionId: 0x1", key: "SessionId: ", want: "0x1"},
+		{line: "Security type: Kerberos\tStatus: 1 type: DISK", key: "type: ", want: "DISK"},
+		{line: "Capability: 0x300047\tSession Status: 1", key: "Status: ", want: ""},
+		{line: `[1] \\filer1\share Mounts: 1 DevInfo: 0x20`, key: "Mounts: ", want: "1"},
+		{line: "Hostname: filer1 Site: Lab type: DISK", key: "type: ", want: "DISK"},
+		{line: "Address: 10.0.0.1 Note: Uses: 3", key: "Uses: ", want: "3"},
+		{line: "Address: 10.0.0.1 Note: Uses: 3", key: "Note: ", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := cifsValue(tt.line, tt.key); got != tt.want {
+			t.Errorf("cifsValue(%q, %q) = %q, want %q", tt.line, tt.key, got, tt.want)
+		}
+	}
+}
@@ -19,7 +19,8 @@ import (
 	"github.com/prometheus/procfs/internal/util"
 )
 
-// CmdLine returns the command line of the kernel.
+// CmdLine returns the command line of the kernel, split at whitespace.
+// Use KernelCmdline to split it like the kernel does, respecting quotes.
 func (fs FS) CmdLine() ([]string, error) {
 	data, err := util.ReadFileNoStat(fs.proc.Path("cmdline"))
 	if err != nil {
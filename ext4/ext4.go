@@ -15,7 +15,12 @@
 package ext4
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/prometheus/procfs/internal/fs"
@@ -25,6 +30,10 @@ import (
 const (
 	sysFSPath     = "fs"
 	sysFSExt4Path = "ext4"
+
+	procFSPath     = "fs"
+	procFSExt4Path = "ext4"
+	procFSJBD2Path = "jbd2"
 )
 
 // Stats contains statistics for a single Btrfs filesystem.
@@ -35,6 +44,88 @@ type Stats struct {
 	Errors   uint64
 	Warnings uint64
 	Messages uint64
+
+	// Write statistics
+	LifetimeWriteKBytes     uint64
+	SessionWriteKBytes      uint64
+	DelayedAllocationBlocks uint64
+	ReservedClusters        uint64
+
+	// Multiblock allocator tunables
+	MBStats            uint64
+	MBMaxToScan        uint64
+	MBMinToScan        uint64
+	MBOrder2Req        uint64
+	MBStreamReq        uint64
+	MBGroupPrealloc    uint64
+	MBMaxInodePrealloc uint64
+
+	// First and last error recorded in the superblock, the times are
+	// seconds since the epoch and 0 if no error was recorded.
+	FirstErrorTime  uint64
+	FirstErrorFunc  string
+	FirstErrorLine  uint64
+	FirstErrorIno   uint64
+	FirstErrorBlock uint64
+	LastErrorTime   uint64
+	LastErrorFunc   string
+	LastErrorLine   uint64
+	LastErrorIno    uint64
+	LastErrorBlock  uint64
+
+	// JournalTask is the PID of the jbd2 journal thread, 0 if the
+	// filesystem has no journal.
+	JournalTask uint64
+
+	// ESShrinkerInfo contains the extent status tree shrinker statistics
+	// from /proc/fs/ext4/<name>/es_shrinker_info.
+	ESShrinkerInfo *ESShrinkerInfo
+	// Journal contains the jbd2 transaction statistics from
+	// /proc/fs/jbd2/<name>-8/info, nil for external or missing journals.
+	Journal *JournalInfo
+}
+
+// ESShrinkerInfo contains the extent status tree shrinker statistics.
+type ESShrinkerInfo struct {
+	Objects            uint64
+	ReclaimableObjects uint64
+	CacheHits          uint64
+	CacheMisses        uint64
+	InodesOnList       uint64
+	AvgScanTimeUs      uint64
+	ShrunkObjects      uint64
+	MaxScanTimeUs      uint64
+}
+
+// JournalInfo contains the jbd2 journal transaction statistics, the average
+// values are computed over the last transactions.
+type JournalInfo struct {
+	Transactions          uint64
+	RequestedTransactions uint64
+	MaxTransactionBlocks  uint64
+
+	AvgWaitingMs                  uint64
+	AvgRequestDelayMs             uint64
+	AvgRunningMs                  uint64
+	AvgLockedMs                   uint64
+	AvgFlushingMs                 uint64
+	AvgLoggingMs                  uint64
+	AvgCommitTimeUs               uint64
+	AvgHandlesPerTransaction      uint64
+	AvgBlocksPerTransaction       uint64
+	AvgLoggedBlocksPerTransaction uint64
+}
+
+// MBGroup contains the multiblock allocator statistics of a block group, as
+// read from /proc/fs/ext4/<name>/mb_groups.
+type MBGroup struct {
+	Group     uint64
+	Free      uint64
+	Fragments uint64
+	First     uint64
+	// Buddy contains the number of free extents of 2^order blocks, indexed
+	// by order.
+	Buddy []uint64
 }
 
 // FS represents the pseudo-filesystems proc and sys, which provides an
@@ -85,9 +176,29 @@ func (fs FS) ProcStat() ([]*Stats, error) {
 		name := filepath.Base(m)
 		s.Name = name
 		for file, p := range map[string]*uint64{
-			"errors_count":  &s.Errors,
-			"warning_count": &s.Warnings,
-			"msg_count":     &s.Messages,
+			"errors_count":              &s.Errors,
+			"warning_count":             &s.Warnings,
+			"msg_count":                 &s.Messages,
+			"lifetime_write_kbytes":     &s.LifetimeWriteKBytes,
+			"session_write_kbytes":      &s.SessionWriteKBytes,
+			"delayed_allocation_blocks": &s.DelayedAllocationBlocks,
+			"reserved_clusters":         &s.ReservedClusters,
+			"mb_stats":                  &s.MBStats,
+			"mb_max_to_scan":            &s.MBMaxToScan,
+			"mb_min_to_scan":            &s.MBMinToScan,
+			"mb_order2_req":             &s.MBOrder2Req,
+			"mb_stream_req":             &s.MBStreamReq,
+			"mb_group_prealloc":         &s.MBGroupPrealloc,
+			"mb_max_inode_prealloc":     &s.MBMaxInodePrealloc,
+			"first_error_time":          &s.FirstErrorTime,
+			"first_error_line":          &s.FirstErrorLine,
+			"first_error_ino":           &s.FirstErrorIno,
+			"first_error_block":         &s.FirstErrorBlock,
+			"last_error_time":           &s.LastErrorTime,
+			"last_error_line":           &s.LastErrorLine,
+			"last_error_ino":            &s.LastErrorIno,
+			"last_error_block":          &s.LastErrorBlock,
+			"journal_task":              &s.JournalTask,
 		} {
 			var val uint64
 			val, err = util.ReadUintFromFile(fs.sys.Path(sysFSPath, sysFSExt4Path, name, file))
@@ -95,9 +206,213 @@ func (fs FS) ProcStat() ([]*Stats, error) {
 				*p = val
 			}
 		}
+		for file, p := range map[string]*string{
+			"first_error_func": &s.FirstErrorFunc,
+			"last_error_func":  &s.LastErrorFunc,
+		} {
+			var val string
+			val, err = util.SysReadFile(fs.sys.Path(sysFSPath, sysFSExt4Path, name, file))
+			if err == nil {
+				*p = val
+			}
+		}
+
+		s.ESShrinkerInfo, err = fs.esShrinkerInfo(name)
+		if err != nil {
+			return nil, err
+		}
+		s.Journal, err = fs.journalInfo(name)
+		if err != nil {
+			return nil, err
+		}
 
 		stats = append(stats, s)
 	}
 
 	return stats, nil
 }
+
+// esShrinkerInfo reads /proc/fs/ext4/<name>/es_shrinker_info, it returns nil
+// if the file does not exist.
+func (fs FS) esShrinkerInfo(name string) (*ESShrinkerInfo, error) {
+	f, err := os.Open(fs.proc.Path(procFSPath, procFSExt4Path, name, "es_shrinker_info"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	info := &ESShrinkerInfo{}
+	var section string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasSuffix(line, ":") {
+			section = strings.TrimSuffix(line, ":")
+			continue
+		}
+
+		value, desc, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid es_shrinker_info line %q", line)
+		}
+		var hits, misses uint64
+		switch {
+		case desc == "cache hits/misses":
+			if _, err := fmt.Sscanf(value, "%d/%d", &hits, &misses); err != nil {
+				return nil, fmt.Errorf("error parsing es_shrinker_info line %q: %w", line, err)
+			}
+			info.CacheHits, info.CacheMisses = hits, misses
+			continue
+		case strings.HasPrefix(desc, "inode ("):
+			// Inode number of the inode with the most objects, not exposed.
+			continue
+		}
+
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing es_shrinker_info line %q: %w", line, err)
+		}
+		switch {
+		case section == "stats" && desc == "objects":
+			info.Objects = v
+		case section == "stats" && desc == "reclaimable objects":
+			info.ReclaimableObjects = v
+		case section == "stats" && desc == "inodes on list":
+			info.InodesOnList = v
+		case section == "average" && desc == "us scan time":
+			info.AvgScanTimeUs = v
+		case section == "average" && desc == "shrunk objects":
+			info.ShrunkObjects = v
+		case section == "maximum" && desc == "us max scan time":
+			info.MaxScanTimeUs = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning es_shrinker_info file: %w", err)
+	}
+
+	return info, nil
+}
+
+// journalInfo reads /proc/fs/jbd2/<name>-8/info, the jbd2 statistics of the
+// internal journal of an ext4 filesystem. It returns nil if the file does not
+// exist.
+func (fs FS) journalInfo(name string) (*JournalInfo, error) {
+	f, err := os.Open(fs.proc.Path(procFSPath, procFSJBD2Path, name+"-8", "info"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseJournalInfo(f)
+}
+
+func parseJournalInfo(r io.Reader) (*JournalInfo, error) {
+	info := &JournalInfo{}
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("empty jbd2 info file: %w", scanner.Err())
+	}
+	header := scanner.Text()
+	if _, err := fmt.Sscanf(header, "%d transactions (%d requested), each up to %d blocks",
+		&info.Transactions, &info.RequestedTransactions, &info.MaxTransactionBlocks); err != nil {
+		return nil, fmt.Errorf("error parsing jbd2 info line %q: %w", header, err)
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "average:" || line == "" {
+			continue
+		}
+
+		value, desc, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid jbd2 info line %q", line)
+		}
+		value = strings.TrimSuffix(strings.TrimSuffix(value, "ms"), "us")
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing jbd2 info line %q: %w", line, err)
+		}
+
+		switch desc {
+		case "waiting for transaction":
+			info.AvgWaitingMs = v
+		case "request delay":
+			info.AvgRequestDelayMs = v
+		case "running transaction":
+			info.AvgRunningMs = v
+		case "transaction was being locked":
+			info.AvgLockedMs = v
+		case "flushing data (in ordered mode)":
+			info.AvgFlushingMs = v
+		case "logging transaction":
+			info.AvgLoggingMs = v
+		case "average transaction commit time":
+			info.AvgCommitTimeUs = v
+		case "handles per transaction":
+			info.AvgHandlesPerTransaction = v
+		case "blocks per transaction":
+			info.AvgBlocksPerTransaction = v
+		case "logged blocks per transaction":
+			info.AvgLoggedBlocksPerTransaction = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning jbd2 info file: %w", err)
+	}
+
+	return info, nil
+}
+
+// MBGroups returns the multiblock allocator statistics of every block group
+// of the named filesystem from /proc/fs/ext4/<name>/mb_groups. They are not
+// part of Stats as large filesystems have many thousands of block groups.
+func (fs FS) MBGroups(name string) ([]MBGroup, error) {
+	f, err := os.Open(fs.proc.Path(procFSPath, procFSExt4Path, name, "mb_groups"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var groups []MBGroup
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Skip the header line "#group: free  frags first [ 2^0 ... ]".
+		if strings.HasPrefix(line, "#group") {
+			continue
+		}
+		// Groups whose bitmap could not be loaded are reported as "#<group>: I/O error".
+		if strings.HasSuffix(line, "I/O error") {
+			continue
+		}
+
+		fields := strings.Fields(strings.NewReplacer("#", "", ":", " ", "[", " ", "]", " ").Replace(line))
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("invalid mb_groups line %q", line)
+		}
+		values, err := util.ParseUint64s(fields)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mb_groups line %q: %w", line, err)
+		}
+		groups = append(groups, MBGroup{
+			Group:     values[0],
+			Free:      values[1],
+			Fragments: values[2],
+			First:     values[3],
+			Buddy:     values[4:],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning mb_groups file: %w", err)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,105 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ext4
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcStat(t *testing.T) {
+	ext4, err := NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access ext4 fs: %v", err)
+	}
+	stats, err := ext4.ProcStat()
+	if err != nil {
+		t.Fatalf("failed to parse ext4 stats: %v", err)
+	}
+
+	want := []*Stats{
+		{
+			Name:                    "sda1",
+			Errors:                  2,
+			Warnings:                1,
+			Messages:                35,
+			LifetimeWriteKBytes:     1302394592,
+			SessionWriteKBytes:      49126048,
+			DelayedAllocationBlocks: 1024,
+			ReservedClusters:        65536,
+			MBMaxToScan:             200,
+			MBMinToScan:             10,
+			MBOrder2Req:             2,
+			MBStreamReq:             16,
+			MBGroupPrealloc:         512,
+			MBMaxInodePrealloc:      512,
+			FirstErrorTime:          1718443203,
+			FirstErrorFunc:          "ext4_lookup",
+			FirstErrorLine:          1855,
+			FirstErrorIno:           2359298,
+			LastErrorTime:           1718529603,
+			LastErrorFunc:           "ext4_validate_block_bitmap",
+			LastErrorLine:           420,
+			LastErrorBlock:          9437186,
+			JournalTask:             451,
+			ESShrinkerInfo: &ESShrinkerInfo{
+				Objects:            24618,
+				ReclaimableObjects: 1024,
+				CacheHits:          1183257,
+				CacheMisses:        2316,
+				InodesOnList:       43,
+				AvgScanTimeUs:      18,
+				ShrunkObjects:      512,
+				MaxScanTimeUs:      94,
+			},
+			Journal: &JournalInfo{
+				Transactions:                  31524,
+				RequestedTransactions:         31510,
+				MaxTransactionBlocks:          8192,
+				AvgRunningMs:                  4977,
+				AvgLoggingMs:                  12,
+				AvgCommitTimeUs:               12540,
+				AvgHandlesPerTransaction:      37,
+				AvgBlocksPerTransaction:       14,
+				AvgLoggedBlocksPerTransaction: 15,
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Fatalf("unexpected ext4 stats (-want +got):\n%s", diff)
+	}
+}
+
+func TestMBGroups(t *testing.T) {
+	ext4, err := NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access ext4 fs: %v", err)
+	}
+	groups, err := ext4.MBGroups("sda1")
+	if err != nil {
+		t.Fatalf("failed to parse ext4 mb_groups: %v", err)
+	}
+
+	want := []MBGroup{
+		{Group: 0, Free: 2832, Fragments: 2, First: 2144, Buddy: []uint64{0, 0, 0, 0, 1, 0, 0, 0, 1, 1, 0, 1, 0, 0}},
+		{Group: 1, Free: 12, Fragments: 1, First: 32756, Buddy: []uint64{0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{Group: 3, Free: 32768, Fragments: 1, First: 0, Buddy: []uint64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}},
+	}
+
+	if diff := cmp.Diff(want, groups); diff != "" {
+		t.Fatalf("unexpected ext4 mb_groups (-want +got):\n%s", diff)
+	}
+}
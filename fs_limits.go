@@ -0,0 +1,167 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// The file system limits are described at
+//
+//	https://docs.kernel.org/admin-guide/sysctl/fs.html
+
+// FileHandles contains the file handle usage of the system, read from
+// /proc/sys/fs/file-nr.
+type FileHandles struct {
+	// Allocated is the number of allocated file handles.
+	Allocated uint64
+	// Unused is the number of allocated but unused file handles. Always 0
+	// since Linux 2.6, which frees unused file handles.
+	Unused uint64
+	// Max is the maximum number of file handles, as in
+	// /proc/sys/fs/file-max.
+	Max uint64
+}
+
+// InodeUsage contains the inode usage of the system, read from
+// /proc/sys/fs/inode-nr. The kernel also exposes these values as the first
+// two fields of /proc/sys/fs/inode-state, which is not read: its remaining
+// fields (preshrink and four dummies) are obsolete and always 0.
+type InodeUsage struct {
+	// NrInodes is the number of allocated inodes.
+	NrInodes uint64
+	// NrFreeInodes is the number of free inodes.
+	NrFreeInodes uint64
+}
+
+// DentryState contains the directory cache usage of the system, read from
+// /proc/sys/fs/dentry-state.
+type DentryState struct {
+	// NrDentry is the number of allocated dentries.
+	NrDentry uint64
+	// NrUnused is the number of unused dentries.
+	NrUnused uint64
+	// AgeLimit is the age in seconds after which dentries can be reclaimed.
+	AgeLimit uint64
+	// WantPages is the number of pages requested by the system.
+	WantPages uint64
+	// NrNegative is the number of unused dentries of files that do not
+	// exist. Only available on kernel 5.0+.
+	NrNegative uint64
+}
+
+// FSLimits contains the usage and limits of file system objects of the
+// system, read from /proc/sys/fs.
+type FSLimits struct {
+	FileHandles FileHandles
+	Inodes      InodeUsage
+	Dentries    DentryState
+	AIONr       *uint64 // /proc/sys/fs/aio-nr
+	AIOMaxNr    *uint64 // /proc/sys/fs/aio-max-nr
+	NrOpen      uint64  // /proc/sys/fs/nr_open
+}
+
+// FileHandles returns the file handle usage of the system from
+// /proc/sys/fs/file-nr.
+func (fs FS) FileHandles() (FileHandles, error) {
+	values, err := fs.sysFSUints("file-nr", 3)
+	if err != nil {
+		return FileHandles{}, err
+	}
+
+	return FileHandles{
+		Allocated: values[0],
+		Unused:    values[1],
+		Max:       values[2],
+	}, nil
+}
+
+// FSLimits returns the usage and limits of file handles, inodes, dentries and
+// asynchronous I/O requests from /proc/sys/fs.
+func (fs FS) FSLimits() (FSLimits, error) {
+	var (
+		limits FSLimits
+		err    error
+	)
+
+	if limits.FileHandles, err = fs.FileHandles(); err != nil {
+		return FSLimits{}, err
+	}
+
+	inodes, err := fs.sysFSUints("inode-nr", 2)
+	if err != nil {
+		return FSLimits{}, err
+	}
+	limits.Inodes = InodeUsage{NrInodes: inodes[0], NrFreeInodes: inodes[1]}
+
+	dentries, err := fs.sysFSUints("dentry-state", 5)
+	if err != nil {
+		return FSLimits{}, err
+	}
+	limits.Dentries = DentryState{
+		NrDentry:   dentries[0],
+		NrUnused:   dentries[1],
+		AgeLimit:   dentries[2],
+		WantPages:  dentries[3],
+		NrNegative: dentries[4],
+	}
+
+	nrOpen, err := util.ReadUintFromFile(fs.proc.Path("sys", "fs", "nr_open"))
+	if err != nil {
+		return FSLimits{}, err
+	}
+	limits.NrOpen = nrOpen
+
+	// The aio files are missing on kernels built without CONFIG_AIO.
+	for file, p := range map[string]**uint64{
+		"aio-nr":     &limits.AIONr,
+		"aio-max-nr": &limits.AIOMaxNr,
+	} {
+		val, err := util.ReadUintFromFile(fs.proc.Path("sys", "fs", file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return FSLimits{}, err
+		}
+		*p = &val
+	}
+
+	return limits, nil
+}
+
+// sysFSUints reads the whitespace separated values of a file in
+// /proc/sys/fs, of which at least n are expected.
+func (fs FS) sysFSUints(file string, n int) ([]uint64, error) {
+	value, err := util.SysReadFile(fs.proc.Path("sys", "fs", file))
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := util.ParseUint64s(strings.Fields(value))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid value in /proc/sys/fs/%s: %w", ErrFileParse, file, err)
+	}
+	if len(values) < n {
+		return nil, fmt.Errorf("%w: expected %d values in /proc/sys/fs/%s, found %d", ErrFileParse, n, file, len(values))
+	}
+
+	return values, nil
+}
@@ -0,0 +1,60 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package procfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileHandles(t *testing.T) {
+	handles, err := getProcFixtures(t).FileHandles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := FileHandles{Allocated: 11936, Unused: 0, Max: 9223372036854775807}
+	if diff := cmp.Diff(want, handles); diff != "" {
+		t.Errorf("unexpected FileHandles (-want +got):\n%s", diff)
+	}
+}
+
+func TestFSLimits(t *testing.T) {
+	limits, err := getProcFixtures(t).FSLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aioNr, aioMaxNr := uint64(2048), uint64(65536)
+	want := FSLimits{
+		FileHandles: FileHandles{Allocated: 11936, Unused: 0, Max: 9223372036854775807},
+		Inodes:      InodeUsage{NrInodes: 238563, NrFreeInodes: 6543},
+		Dentries: DentryState{
+			NrDentry:   276123,
+			NrUnused:   252305,
+			AgeLimit:   45,
+			WantPages:  0,
+			NrNegative: 1923,
+		},
+		AIONr:    &aioNr,
+		AIOMaxNr: &aioMaxNr,
+		NrOpen:   1048576,
+	}
+	if diff := cmp.Diff(want, limits); diff != "" {
+		t.Errorf("unexpected FSLimits (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// A Resource is a range of physical memory or I/O ports claimed by the kernel
+// or a device, parsed from /proc/iomem or /proc/ioports. Resources are nested:
+// the children of a resource are subranges of it.
+type Resource struct {
+	// Start and End are the inclusive bounds of the range. Both are 0
+	// unless read with root privileges.
+	Start    uint64
+	End      uint64
+	Name     string
+	Children []Resource
+}
+
+// Size returns the number of bytes or ports in the range of the resource.
+func (r Resource) Size() uint64 {
+	return r.End - r.Start + 1
+}
+
+// IOMem reads the physical memory map from /proc/iomem.
+func (fs FS) IOMem() ([]Resource, error) {
+	return fs.resources("iomem")
+}
+
+// IOPorts reads the I/O port map from /proc/ioports.
+func (fs FS) IOPorts() ([]Resource, error) {
+	return fs.resources("ioports")
+}
+
+func (fs FS) resources(file string) ([]Resource, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path(file))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResources(bytes.NewReader(data))
+}
+
+type resourceLine struct {
+	depth    int
+	resource Resource
+}
+
+// parseResources parses /proc/iomem and /proc/ioports. Each line describes
+// one resource, indented by two spaces per nesting level:
+//
+//	00100000-bffdffff : System RAM
+//	  1a000000-1b002226 : Kernel code
+func parseResources(r io.Reader) ([]Resource, error) {
+	var lines []resourceLine
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		span, name, ok := strings.Cut(strings.TrimSpace(line), " : ")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid resource line %q", ErrFileParse, line)
+		}
+		start, end, ok := strings.Cut(span, "-")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid resource range in %q", ErrFileParse, line)
+		}
+
+		res := Resource{Name: name}
+		var err error
+		if res.Start, err = strconv.ParseUint(start, 16, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid resource range in %q: %w", ErrFileParse, line, err)
+		}
+		if res.End, err = strconv.ParseUint(end, 16, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid resource range in %q: %w", ErrFileParse, line, err)
+		}
+
+		depth := indent / 2
+		prev := -1
+		if len(lines) > 0 {
+			prev = lines[len(lines)-1].depth
+		}
+		if depth > prev+1 {
+			return nil, fmt.Errorf("%w: resource without parent in %q", ErrFileParse, line)
+		}
+		lines = append(lines, resourceLine{depth: depth, resource: res})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	resources, _ := buildResources(lines, 0)
+	return resources, nil
+}
+
+// buildResources builds the resources at depth from the start of lines, and
+// returns them with the lines following them.
+func buildResources(lines []resourceLine, depth int) ([]Resource, []resourceLine) {
+	resources := []Resource{}
+	for len(lines) > 0 && lines[0].depth == depth {
+		res := lines[0].resource
+		if len(lines) > 1 && lines[1].depth > depth {
+			res.Children, lines = buildResources(lines[1:], depth+1)
+		} else {
+			lines = lines[1:]
+		}
+		resources = append(resources, res)
+	}
+	return resources, lines
+}
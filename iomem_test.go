@@ -0,0 +1,91 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIOMem(t *testing.T) {
+	iomem, err := getProcFixtures(t).IOMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 10, len(iomem); want != got {
+		t.Fatalf("want %d top-level resources, got %d", want, got)
+	}
+
+	want := Resource{
+		Start: 0xc0000000,
+		End:   0xfebfffff,
+		Name:  "PCI Bus 0000:00",
+		Children: []Resource{
+			{
+				Start: 0xfc000000,
+				End:   0xfdffffff,
+				Name:  "0000:00:02.0",
+				Children: []Resource{
+					{Start: 0xfc000000, End: 0xfdffffff, Name: "bochs-drm"},
+				},
+			},
+			{Start: 0xfeb80000, End: 0xfebbffff, Name: "0000:00:03.0"},
+		},
+	}
+	if diff := cmp.Diff(want, iomem[7]); diff != "" {
+		t.Errorf("unexpected resource (-want +got):\n%s", diff)
+	}
+
+	var ram uint64
+	for _, r := range iomem {
+		if r.Name == "System RAM" {
+			ram += r.Size()
+		}
+	}
+	if want, got := uint64(0x9ec00+0xbfee0000+0x140000000), ram; want != got {
+		t.Errorf("want System RAM size %d, got %d", want, got)
+	}
+}
+
+func TestIOPorts(t *testing.T) {
+	ioports, err := getProcFixtures(t).IOPorts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 3, len(ioports); want != got {
+		t.Fatalf("want %d top-level resources, got %d", want, got)
+	}
+	if want, got := 4, len(ioports[0].Children); want != got {
+		t.Errorf("want %d children, got %d", want, got)
+	}
+	if want, got := "virtio-pci-legacy", ioports[2].Children[0].Children[0].Name; want != got {
+		t.Errorf("want nested resource %q, got %q", want, got)
+	}
+}
+
+func TestParseResourcesInvalid(t *testing.T) {
+	for _, testdata := range []string{
+		"0000-0cf7 PCI Bus 0000:00\n",
+		"0000-0cf7 : PCI Bus 0000:00\n    0000-001f : dma1\n",
+		"zzzz-0cf7 : PCI Bus 0000:00\n",
+	} {
+		if _, err := parseResources(strings.NewReader(testdata)); err == nil {
+			t.Errorf("expected error parsing %q", testdata)
+		}
+	}
+}
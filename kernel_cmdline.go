@@ -0,0 +1,249 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// KernelCmdlineParam is a parameter of the kernel command line.
+type KernelCmdlineParam struct {
+	// Name is the name of the parameter, e.g. "root" or "vt.handoff".
+	Name string
+	// Module is the module of a parameter of the form module.param, e.g.
+	// "vt" for "vt.handoff". Empty for other parameters.
+	Module string
+	// Value is the value of the parameter with quotes removed.
+	Value string
+	// HasValue is false for flags, i.e. parameters without "=".
+	HasValue bool
+}
+
+// KernelCmdline is the kernel command line, split into parameters the way
+// the kernel does it.
+type KernelCmdline struct {
+	// Params are the kernel parameters, in command line order.
+	Params []KernelCmdlineParam
+	// InitArgs are the arguments following "--", which the kernel passes
+	// to init.
+	InitArgs []string
+}
+
+// KernelCmdline returns the parsed command line of the kernel, read from
+// /proc/cmdline.
+func (fs FS) KernelCmdline() (*KernelCmdline, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path("cmdline"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseKernelCmdline(string(data)), nil
+}
+
+// Has reports whether the parameter name is present on the command line,
+// with or without value. As in the kernel, dashes and underscores in names
+// are equivalent.
+func (c KernelCmdline) Has(name string) bool {
+	for _, p := range c.Params {
+		if paramNameEqual(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Flag reports whether the parameter name is present on the command line
+// without value, e.g. "quiet".
+func (c KernelCmdline) Flag(name string) bool {
+	for _, p := range c.Params {
+		if !p.HasValue && paramNameEqual(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the value of the parameter name. If the parameter is
+// repeated, the last value is returned, which is the one most parameters
+// take effect with.
+func (c KernelCmdline) Value(name string) (string, bool) {
+	values := c.Values(name)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// Values returns all values of a repeated parameter, e.g. "console", in
+// command line order.
+func (c KernelCmdline) Values(name string) []string {
+	var values []string
+	for _, p := range c.Params {
+		if p.HasValue && paramNameEqual(p.Name, name) {
+			values = append(values, p.Value)
+		}
+	}
+	return values
+}
+
+// ModuleParams returns the parameters of the form module.param=value given
+// for module, keyed by parameter name.
+func (c KernelCmdline) ModuleParams(module string) map[string]string {
+	params := map[string]string{}
+	for _, p := range c.Params {
+		if p.Module != "" && paramNameEqual(p.Module, module) {
+			params[strings.TrimPrefix(p.Name, p.Module+".")] = p.Value
+		}
+	}
+	return params
+}
+
+func paramNameEqual(a, b string) bool {
+	return strings.ReplaceAll(a, "-", "_") == strings.ReplaceAll(b, "-", "_")
+}
+
+// parseKernelCmdline splits a kernel command line like next_arg in
+// kernel/params.c: double quotes protect whitespace, and are removed
+// around the whole argument and around the value.
+func parseKernelCmdline(cmdline string) *KernelCmdline {
+	var (
+		c    = KernelCmdline{}
+		args = strings.TrimLeft(cmdline, " \t\n\x00")
+	)
+
+	for args != "" {
+		var arg string
+		arg, args = nextKernelArg(args)
+
+		if arg == "--" && c.InitArgs == nil {
+			c.InitArgs = []string{}
+			continue
+		}
+		if c.InitArgs != nil {
+			c.InitArgs = append(c.InitArgs, arg)
+			continue
+		}
+
+		var p KernelCmdlineParam
+		p.Name, p.Value, p.HasValue = strings.Cut(arg, "=")
+		if p.HasValue && strings.HasPrefix(p.Value, `"`) {
+			p.Value = strings.TrimSuffix(p.Value[1:], `"`)
+		}
+		if module, _, ok := strings.Cut(p.Name, "."); ok {
+			p.Module = module
+		}
+		c.Params = append(c.Params, p)
+	}
+
+	return &c
+}
+
+// nextKernelArg returns the next argument of the command line, with the
+// quotes around the whole argument removed, and the remaining command line.
+func nextKernelArg(args string) (string, string) {
+	quoted := strings.HasPrefix(args, `"`)
+	if quoted {
+		args = args[1:]
+	}
+
+	inQuote := quoted
+	i := 0
+	for ; i < len(args); i++ {
+		if !inQuote && strings.ContainsRune(" \t\n\x00", rune(args[i])) {
+			break
+		}
+		if args[i] == '"' {
+			inQuote = !inQuote
+		}
+	}
+
+	arg := args[:i]
+	if quoted {
+		arg = strings.TrimSuffix(arg, `"`)
+	}
+
+	return arg, strings.TrimLeft(args[i:], " \t\n\x00")
+}
+
+// BootConfig maps the keys of the boot configuration to their values. Keys
+// without value map to a single empty value.
+//
+// See: https://docs.kernel.org/admin-guide/bootconfig.html
+type BootConfig map[string][]string
+
+// BootConfig returns the boot configuration read from /proc/bootconfig.
+// The file only exists on kernel 5.5+ built with CONFIG_BOOT_CONFIG.
+func (fs FS) BootConfig() (BootConfig, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path("bootconfig"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBootConfig(data)
+}
+
+// parseBootConfig parses /proc/bootconfig. Lines look like:
+//
+//	kernel.console = "ttyS0,115200", "tty0"
+func parseBootConfig(data []byte) (BootConfig, error) {
+	config := BootConfig{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// The kernel appends the command line given by the boot loader as
+		// comments.
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, rest, ok := strings.Cut(line, " = ")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid bootconfig line %q", ErrFileParse, line)
+		}
+
+		var values []string
+		for {
+			// Values containing double quotes are quoted with single
+			// quotes.
+			if len(rest) < 2 || (rest[0] != '"' && rest[0] != '\'') {
+				return nil, fmt.Errorf("%w: invalid bootconfig value in %q", ErrFileParse, line)
+			}
+			end := strings.IndexByte(rest[1:], rest[0])
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated bootconfig value in %q", ErrFileParse, line)
+			}
+			values = append(values, rest[1:end+1])
+			rest = rest[end+2:]
+			if rest == "" {
+				break
+			}
+			if rest, ok = strings.CutPrefix(rest, ", "); !ok {
+				return nil, fmt.Errorf("%w: invalid bootconfig value in %q", ErrFileParse, line)
+			}
+		}
+		config[key] = values
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,152 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKernelCmdline(t *testing.T) {
+	cmdline, err := getProcFixtures(t).KernelCmdline()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &KernelCmdline{
+		Params: []KernelCmdlineParam{
+			{Name: "BOOT_IMAGE", Value: "/vmlinuz-5.11.0-22-generic", HasValue: true},
+			{Name: "root", Value: "UUID=456a0345-450d-4f7b-b7c9-43e3241d99ad", HasValue: true},
+			{Name: "ro"},
+			{Name: "quiet"},
+			{Name: "splash"},
+			{Name: "vt.handoff", Module: "vt", Value: "7", HasValue: true},
+		},
+	}
+	if diff := cmp.Diff(want, cmdline); diff != "" {
+		t.Errorf("unexpected KernelCmdline (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseKernelCmdline(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmdline string
+		want    *KernelCmdline
+	}{
+		{
+			name:    "quoted value",
+			cmdline: `dyndbg="file ext4/* +p" quiet` + "\n",
+			want: &KernelCmdline{
+				Params: []KernelCmdlineParam{
+					{Name: "dyndbg", Value: "file ext4/* +p", HasValue: true},
+					{Name: "quiet"},
+				},
+			},
+		},
+		{
+			name:    "quoted argument",
+			cmdline: `"acpi_osi=!Windows 2012"  "nomodeset"`,
+			want: &KernelCmdline{
+				Params: []KernelCmdlineParam{
+					{Name: "acpi_osi", Value: "!Windows 2012", HasValue: true},
+					{Name: "nomodeset"},
+				},
+			},
+		},
+		{
+			name:    "init arguments",
+			cmdline: "console=tty0 init=/bin/sh -- -s single --",
+			want: &KernelCmdline{
+				Params: []KernelCmdlineParam{
+					{Name: "console", Value: "tty0", HasValue: true},
+					{Name: "init", Value: "/bin/sh", HasValue: true},
+				},
+				InitArgs: []string{"-s", "single", "--"},
+			},
+		},
+		{
+			name:    "empty",
+			cmdline: "\n",
+			want:    &KernelCmdline{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, parseKernelCmdline(tt.cmdline)); diff != "" {
+				t.Errorf("unexpected KernelCmdline (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKernelCmdlineLookup(t *testing.T) {
+	c := parseKernelCmdline("console=ttyS0,115200 console=tty0 quiet nvme_core.default_ps_max_latency_us=0 nvme-core.io_timeout=255 log_buf_len=1M mitigations")
+
+	if want, got := []string{"ttyS0,115200", "tty0"}, c.Values("console"); !cmp.Equal(want, got) {
+		t.Errorf("want console values %v, got %v", want, got)
+	}
+	if got, ok := c.Value("console"); !ok || got != "tty0" {
+		t.Errorf("want last console value tty0, got %q", got)
+	}
+	if _, ok := c.Value("quiet"); ok {
+		t.Error("want no value for flag quiet")
+	}
+	if !c.Flag("quiet") || c.Flag("console") || c.Flag("splash") {
+		t.Error("unexpected flags")
+	}
+	if !c.Has("log-buf-len") || c.Has("splash") {
+		t.Error("unexpected parameters")
+	}
+
+	want := map[string]string{
+		"default_ps_max_latency_us": "0",
+		"io_timeout":                "255",
+	}
+	if diff := cmp.Diff(want, c.ModuleParams("nvme_core")); diff != "" {
+		t.Errorf("unexpected module parameters (-want +got):\n%s", diff)
+	}
+}
+
+func TestBootConfig(t *testing.T) {
+	config, err := getProcFixtures(t).BootConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := BootConfig{
+		"kernel.console":                        {"ttyS0,115200", "tty0"},
+		"kernel.ftrace.event":                   {`sched:sched_switch if prev_comm == "kworker"`},
+		"init.systemd.unified_cgroup_hierarchy": {"1"},
+		"kernel.nosmt":                          {""},
+	}
+	if diff := cmp.Diff(want, config); diff != "" {
+		t.Errorf("unexpected BootConfig (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseBootConfigInvalid(t *testing.T) {
+	for _, testdata := range []string{
+		"kernel.console\n",
+		"kernel.console = ttyS0\n",
+		`kernel.console = "ttyS0` + "\n",
+		`kernel.console = "ttyS0" "tty0"` + "\n",
+	} {
+		if _, err := parseBootConfig([]byte(testdata)); err == nil {
+			t.Errorf("expected error parsing %q", testdata)
+		}
+	}
+}
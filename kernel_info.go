@@ -0,0 +1,161 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package procfs
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// TaintFlag is a reason for the kernel to be tainted, numbered by its bit in
+// /proc/sys/kernel/tainted.
+//
+// See: https://docs.kernel.org/admin-guide/tainted-kernels.html
+type TaintFlag uint
+
+// The taint flags known to this package.
+const (
+	TaintProprietaryModule   TaintFlag = iota // P
+	TaintForcedModule                         // F
+	TaintCPUOutOfSpec                         // S
+	TaintForcedRmmod                          // R
+	TaintMachineCheck                         // M
+	TaintBadPage                              // B
+	TaintUser                                 // U
+	TaintDie                                  // D
+	TaintOverriddenACPITable                  // A
+	TaintWarn                                 // W
+	TaintCrap                                 // C
+	TaintFirmwareWorkaround                   // I
+	TaintOOTModule                            // O
+	TaintUnsignedModule                       // E
+	TaintSoftLockup                           // L
+	TaintLivepatch                            // K
+	TaintAux                                  // X
+	TaintRandstruct                           // T
+	TaintTest                                 // N
+)
+
+const taintLetters = "PFSRMBUDAWCIOELKXTN"
+
+// String returns the letter the kernel uses for the taint flag in oops
+// messages and /proc/modules, e.g. "O" for an out-of-tree module. Flags
+// unknown to this package are named by their bit, e.g. "bit 19".
+func (f TaintFlag) String() string {
+	if int(f) < len(taintLetters) {
+		return taintLetters[f : f+1]
+	}
+	return "bit " + strconv.FormatUint(uint64(f), 10)
+}
+
+// KernelTaint is the bitmap of taint flags of the kernel, as found in
+// /proc/sys/kernel/tainted. It is 0 for an untainted kernel.
+type KernelTaint uint64
+
+// Has reports whether the kernel is tainted for the reason f.
+func (t KernelTaint) Has(f TaintFlag) bool {
+	return f < 64 && t&(1<<f) != 0
+}
+
+// Flags returns the taint flags set in the bitmap, in ascending order.
+func (t KernelTaint) Flags() []TaintFlag {
+	var flags []TaintFlag
+	for f := TaintFlag(0); f < 64; f++ {
+		if t.Has(f) {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// String returns the letters of the taint flags set in the bitmap, e.g.
+// "POE", or an empty string for an untainted kernel.
+func (t KernelTaint) String() string {
+	var b strings.Builder
+	for _, f := range t.Flags() {
+		b.WriteString(f.String())
+	}
+	return b.String()
+}
+
+// KernelInfo contains information identifying the running kernel and the
+// current boot.
+type KernelInfo struct {
+	// Banner is the kernel banner from /proc/version, including the
+	// compiler used to build the kernel.
+	Banner string
+	// OSRelease is the kernel release, e.g. "6.8.0-45-generic".
+	OSRelease string
+	// Version is the kernel build version, e.g.
+	// "#45-Ubuntu SMP PREEMPT_DYNAMIC Fri Aug 30 12:02:04 UTC 2024".
+	Version    string
+	Hostname   string
+	DomainName string
+	Tainted    KernelTaint
+	// BootID is a random UUID generated at boot, which changes on every
+	// reboot.
+	BootID string
+	// BootParameters maps the parameters of the kernel command line to
+	// their values. Parameters without value map to an empty string, and
+	// repeated parameters to their last value. See KernelCmdline for
+	// details.
+	BootParameters map[string]string
+}
+
+// KernelInfo returns information about the running kernel from
+// /proc/version, /proc/sys/kernel and /proc/cmdline.
+func (fs FS) KernelInfo() (KernelInfo, error) {
+	var info KernelInfo
+
+	banner, err := util.ReadFileNoStat(fs.proc.Path("version"))
+	if err != nil {
+		return KernelInfo{}, err
+	}
+	info.Banner = strings.TrimSpace(string(banner))
+
+	for file, p := range map[string]*string{
+		"osrelease":      &info.OSRelease,
+		"version":        &info.Version,
+		"hostname":       &info.Hostname,
+		"domainname":     &info.DomainName,
+		"random/boot_id": &info.BootID,
+	} {
+		val, err := util.SysReadFile(fs.proc.Path("sys", "kernel", file))
+		if err != nil {
+			return KernelInfo{}, err
+		}
+		*p = val
+	}
+
+	tainted, err := util.ReadUintFromFile(fs.proc.Path("sys", "kernel", "tainted"))
+	if err != nil {
+		return KernelInfo{}, err
+	}
+	info.Tainted = KernelTaint(tainted)
+
+	cmdline, err := fs.KernelCmdline()
+	if err != nil {
+		return KernelInfo{}, err
+	}
+	info.BootParameters = make(map[string]string, len(cmdline.Params))
+	for _, p := range cmdline.Params {
+		info.BootParameters[p.Name] = p.Value
+	}
+
+	return info, nil
+}
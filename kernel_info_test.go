@@ -0,0 +1,67 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package procfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKernelInfo(t *testing.T) {
+	info, err := getProcFixtures(t).KernelInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := KernelInfo{
+		Banner:     "Linux version 6.8.0-45-generic (buildd@lcy02-amd64-075) (x86_64-linux-gnu-gcc-13 (Ubuntu 13.2.0-23ubuntu4) 13.2.0, GNU ld (GNU Binutils for Ubuntu) 2.42) #45-Ubuntu SMP PREEMPT_DYNAMIC Fri Aug 30 12:02:04 UTC 2024",
+		OSRelease:  "6.8.0-45-generic",
+		Version:    "#45-Ubuntu SMP PREEMPT_DYNAMIC Fri Aug 30 12:02:04 UTC 2024",
+		Hostname:   "prometheus-01",
+		DomainName: "(none)",
+		Tainted:    KernelTaint(12801),
+		BootID:     "0d4ff2a8-3d8c-4ec5-a9b1-8c3f5a2d7e61",
+		BootParameters: map[string]string{
+			"BOOT_IMAGE": "/vmlinuz-5.11.0-22-generic",
+			"root":       "UUID=456a0345-450d-4f7b-b7c9-43e3241d99ad",
+			"ro":         "",
+			"quiet":      "",
+			"splash":     "",
+			"vt.handoff": "7",
+		},
+	}
+	if diff := cmp.Diff(want, info); diff != "" {
+		t.Errorf("unexpected KernelInfo (-want +got):\n%s", diff)
+	}
+}
+
+func TestKernelTaint(t *testing.T) {
+	taint := KernelTaint(12801)
+
+	if !taint.Has(TaintOOTModule) || taint.Has(TaintDie) {
+		t.Errorf("unexpected taint flags %v", taint.Flags())
+	}
+	if want, got := "PWOE", taint.String(); want != got {
+		t.Errorf("want taint %q, got %q", want, got)
+	}
+	if want, got := "", KernelTaint(0).String(); want != got {
+		t.Errorf("want taint %q, got %q", want, got)
+	}
+	if want, got := "Nbit 20", KernelTaint(1<<TaintTest|1<<20).String(); want != got {
+		t.Errorf("want taint %q, got %q", want, got)
+	}
+}
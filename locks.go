@@ -0,0 +1,197 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// LockEOF is the End of a lock extending to the end of the file.
+const LockEOF = math.MaxInt64
+
+// Lock is a file lock or lease, parsed from a line of /proc/locks or a lock
+// line of /proc/[pid]/fdinfo/[fd].
+type Lock struct {
+	// ID is the position of the lock in /proc/locks. Blocked waiters share
+	// the ID of the lock they wait for.
+	ID int64
+	// Type is the type of the lock: "POSIX", "FLOCK", "OFDLCK", "LEASE" or
+	// "DELEG".
+	Type string
+	// Mode is "ADVISORY" or "MANDATORY" for locks, and "ACTIVE", "BREAKING"
+	// or "BREAKER" for leases.
+	Mode string
+	// Access is "READ" or "WRITE", or "UNLCK" for a lease being broken.
+	Access string
+	// PID is the process holding or waiting for the lock, -1 for OFD locks
+	// and 0 if the process is not visible in the PID namespace of the
+	// reader.
+	PID int
+	// Major, Minor and Inode identify the locked file.
+	Major uint32
+	Minor uint32
+	Inode uint64
+	// Start and End are the inclusive byte range of the lock. End is LockEOF
+	// if the lock extends to the end of the file.
+	Start uint64
+	End   uint64
+	// BlockedOn is the lock this lock is waiting for, nil if the lock is
+	// held.
+	BlockedOn *Lock
+	// Owner is the process holding or waiting for the lock, nil if the
+	// process is unknown or has exited.
+	Owner *Proc
+}
+
+// Locks reads the file locks and leases of the system from /proc/locks.
+// Locks waiting for another lock are listed after it, with BlockedOn
+// pointing to it.
+func (fs FS) Locks() ([]Lock, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path("locks"))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		locks   = []Lock{}
+		blocker = []int{}
+		// waiting maps the index of each waiter to the index of the lock
+		// it is blocked on. The pointers are set once all locks are read.
+		waiting = map[int]int{}
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		lock, depth, err := parseLock(line)
+		if err != nil {
+			return nil, err
+		}
+		lock.Owner = fs.lockOwner(lock.PID)
+
+		// The waiters of a lock follow it, indented by their depth in the
+		// chain of blocked locks.
+		if depth > len(blocker) || (depth > 0 && locks[blocker[depth-1]].ID != lock.ID) {
+			return nil, fmt.Errorf("%w: lock without blocker in %q", ErrFileParse, line)
+		}
+		blocker = append(blocker[:depth], len(locks))
+		if depth > 0 {
+			waiting[len(locks)] = blocker[depth-1]
+		}
+		locks = append(locks, lock)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for waiter, lock := range waiting {
+		locks[waiter].BlockedOn = &locks[lock]
+	}
+
+	return locks, nil
+}
+
+func (fs FS) lockOwner(pid int) *Proc {
+	if pid <= 0 {
+		return nil
+	}
+	p, err := fs.Proc(pid)
+	if err != nil {
+		return nil
+	}
+	return &p
+}
+
+// parseLock parses a line of /proc/locks and returns the lock and its depth
+// in the chain of blocked locks, 0 for held locks. Lines look like:
+//
+//	1: POSIX  ADVISORY  WRITE 26231 fd:00:2097155 0 EOF
+//	1: -> POSIX  ADVISORY  WRITE 26233 fd:00:2097155 0 EOF
+func parseLock(line string) (Lock, int, error) {
+	id, rest, ok := strings.Cut(line, ":")
+	if !ok {
+		return Lock{}, 0, fmt.Errorf("%w: invalid lock line %q", ErrFileParse, line)
+	}
+
+	var (
+		lock  Lock
+		depth int
+		err   error
+	)
+	if lock.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return Lock{}, 0, fmt.Errorf("%w: invalid lock ID in %q: %w", ErrFileParse, line, err)
+	}
+
+	// Waiters are marked by "->", right-aligned by one more space for each
+	// level of blocking.
+	rest = strings.TrimPrefix(rest, " ")
+	trimmed := strings.TrimLeft(rest, " ")
+	if after, ok := strings.CutPrefix(trimmed, "->"); ok {
+		depth = len(rest) - len(trimmed) + 1
+		trimmed = after
+	}
+
+	fields := strings.Fields(trimmed)
+	if len(fields) != 7 {
+		return Lock{}, 0, fmt.Errorf("%w: invalid number of fields in lock line %q", ErrFileParse, line)
+	}
+	lock.Type, lock.Mode, lock.Access = fields[0], fields[1], fields[2]
+
+	if lock.PID, err = strconv.Atoi(fields[3]); err != nil {
+		return Lock{}, 0, fmt.Errorf("%w: invalid lock PID in %q: %w", ErrFileParse, line, err)
+	}
+
+	// Locks on files without inode are shown as "<none>:0".
+	if fields[4] != "<none>:0" {
+		dev := strings.Split(fields[4], ":")
+		if len(dev) != 3 {
+			return Lock{}, 0, fmt.Errorf("%w: invalid lock device in %q", ErrFileParse, line)
+		}
+		major, err := strconv.ParseUint(dev[0], 16, 32)
+		if err != nil {
+			return Lock{}, 0, fmt.Errorf("%w: invalid lock device in %q: %w", ErrFileParse, line, err)
+		}
+		minor, err := strconv.ParseUint(dev[1], 16, 32)
+		if err != nil {
+			return Lock{}, 0, fmt.Errorf("%w: invalid lock device in %q: %w", ErrFileParse, line, err)
+		}
+		lock.Major, lock.Minor = uint32(major), uint32(minor)
+		if lock.Inode, err = strconv.ParseUint(dev[2], 10, 64); err != nil {
+			return Lock{}, 0, fmt.Errorf("%w: invalid lock inode in %q: %w", ErrFileParse, line, err)
+		}
+	}
+
+	if lock.Start, err = strconv.ParseUint(fields[5], 10, 64); err != nil {
+		return Lock{}, 0, fmt.Errorf("%w: invalid lock start in %q: %w", ErrFileParse, line, err)
+	}
+	lock.End = LockEOF
+	if fields[6] != "EOF" {
+		if lock.End, err = strconv.ParseUint(fields[6], 10, 64); err != nil {
+			return Lock{}, 0, fmt.Errorf("%w: invalid lock end in %q: %w", ErrFileParse, line, err)
+		}
+	}
+
+	return lock, depth, nil
+}
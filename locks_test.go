@@ -0,0 +1,96 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestLocks(t *testing.T) {
+	locks, err := getProcFixtures(t).Locks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Lock{
+		{ID: 1, Type: "POSIX", Mode: "ADVISORY", Access: "WRITE", PID: 26231, Major: 0xfd, Inode: 2097155, End: LockEOF},
+		{ID: 1, Type: "POSIX", Mode: "ADVISORY", Access: "WRITE", PID: 26233, Major: 0xfd, Inode: 2097155, End: LockEOF},
+		{ID: 1, Type: "POSIX", Mode: "ADVISORY", Access: "READ", PID: 26234, Major: 0xfd, Inode: 2097155, End: 99},
+		{ID: 2, Type: "FLOCK", Mode: "ADVISORY", Access: "WRITE", PID: 26232, Major: 0xfd, Inode: 2097156, End: LockEOF},
+		{ID: 3, Type: "OFDLCK", Mode: "ADVISORY", Access: "READ", PID: -1, Major: 8, Minor: 1, Inode: 1048578, Start: 128, End: 1023},
+		{ID: 4, Type: "LEASE", Mode: "ACTIVE", Access: "READ", PID: 99999, Major: 8, Minor: 1, Inode: 1048579, End: LockEOF},
+		{ID: 5, Type: "DELEG", Mode: "ACTIVE", Access: "READ", PID: 0, Minor: 0x2f, Inode: 77, End: LockEOF},
+	}
+	if diff := cmp.Diff(want, locks, cmpopts.IgnoreFields(Lock{}, "BlockedOn", "Owner")); diff != "" {
+		t.Errorf("unexpected locks (-want +got):\n%s", diff)
+	}
+
+	// The waiters form a chain: 26234 waits for 26233, which waits for 26231.
+	for i, blocker := range []int{-1, 0, 1, -1, -1, -1, -1} {
+		switch {
+		case blocker < 0 && locks[i].BlockedOn != nil:
+			t.Errorf("lock %d: want held lock, got blocked on PID %d", i, locks[i].BlockedOn.PID)
+		case blocker >= 0 && locks[i].BlockedOn != &locks[blocker]:
+			t.Errorf("lock %d: want blocked on lock %d, got %v", i, blocker, locks[i].BlockedOn)
+		}
+	}
+
+	for i, pid := range []int{26231, 26233, 26234, 26232, 0, 0, 0} {
+		switch {
+		case pid == 0 && locks[i].Owner != nil:
+			t.Errorf("lock %d: want no owner, got PID %d", i, locks[i].Owner.PID)
+		case pid != 0 && (locks[i].Owner == nil || locks[i].Owner.PID != pid):
+			t.Errorf("lock %d: want owner PID %d, got %v", i, pid, locks[i].Owner)
+		}
+	}
+}
+
+func TestParseLockInvalid(t *testing.T) {
+	for _, line := range []string{
+		"1 POSIX  ADVISORY  WRITE 26231 fd:00:2097155 0 EOF",
+		"x: POSIX  ADVISORY  WRITE 26231 fd:00:2097155 0 EOF",
+		"1: POSIX  ADVISORY  WRITE 26231 fd:00:2097155 0",
+		"1: POSIX  ADVISORY  WRITE 26231 fd:00 0 EOF",
+		"1: POSIX  ADVISORY  WRITE 26231 fd:00:2097155 0 100x",
+	} {
+		if _, _, err := parseLock(line); err == nil {
+			t.Errorf("expected error parsing %q", line)
+		}
+	}
+}
+
+func TestFDInfoLocks(t *testing.T) {
+	p, err := getProcFixtures(t).Proc(26232)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fdinfo, err := p.FDInfo("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Lock{
+		{ID: 1, Type: "FLOCK", Mode: "ADVISORY", Access: "WRITE", PID: 26232, Major: 0xfd, Inode: 2097156, End: LockEOF},
+	}
+	if diff := cmp.Diff(want, fdinfo.Locks, cmpopts.IgnoreFields(Lock{}, "Owner")); diff != "" {
+		t.Errorf("unexpected locks (-want +got):\n%s", diff)
+	}
+	if owner := fdinfo.Locks[0].Owner; owner == nil || owner.PID != 26232 {
+		t.Errorf("want owner PID 26232, got %v", owner)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// Module is a loaded kernel module, parsed from a line of /proc/modules.
+// Further attributes of a module, e.g. its version and parameters, are
+// available from /sys/module/<name> through the sysfs package, whose
+// FS.LoadedModules joins them with the modules returned by FS.Modules.
+type Module struct {
+	Name string
+	// Size is the memory size of the module in bytes.
+	Size uint64
+	// RefCount is the number of references to the module, -1 if the kernel
+	// does not support unloading modules.
+	RefCount int64
+	// UsedBy are the names of the modules using this module. The kernel
+	// lists "[permanent]" for modules that can not be unloaded and
+	// "[unsafe]" for modules forcibly loaded without unload support.
+	UsedBy []string
+	// State is the load state of the module: "Live", "Loading" or
+	// "Unloading".
+	State string
+	// Address is the load address of the module. It is 0 unless read with
+	// root privileges.
+	Address uint64
+	// Taints are the taint flags of the module, e.g. "OE" for an unsigned
+	// out-of-tree module. Empty if the module does not taint the kernel.
+	Taints string
+}
+
+// Modules reads the loaded kernel modules from /proc/modules.
+func (fs FS) Modules() ([]Module, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path("modules"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseModules(bytes.NewReader(data))
+}
+
+// parseModules parses /proc/modules. Lines look like:
+//
+//	nvidia 56115200 45 nvidia_drm,nvidia_modeset, Live 0xffffffffc0c00000 (POE)
+func parseModules(r io.Reader) ([]Module, error) {
+	modules := []Module{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) < 6 {
+			return nil, fmt.Errorf("%w: invalid number of fields in module line %q", ErrFileParse, line)
+		}
+
+		var (
+			m   = Module{Name: parts[0], RefCount: -1, State: parts[4]}
+			err error
+		)
+		if m.Size, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid module size in %q: %w", ErrFileParse, line, err)
+		}
+		if parts[2] != "-" {
+			if m.RefCount, err = strconv.ParseInt(parts[2], 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: invalid module reference count in %q: %w", ErrFileParse, line, err)
+			}
+		}
+		if parts[3] != "-" {
+			for u := range strings.SplitSeq(parts[3], ",") {
+				if u != "" {
+					m.UsedBy = append(m.UsedBy, u)
+				}
+			}
+		}
+		if m.Address, err = strconv.ParseUint(strings.TrimPrefix(parts[5], "0x"), 16, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid module address in %q: %w", ErrFileParse, line, err)
+		}
+		if len(parts) > 6 {
+			// The taint flags are followed by "+" while the module is
+			// loading and "-" while it is unloading.
+			m.Taints = strings.TrimRight(strings.Trim(parts[6], "()"), "+-")
+		}
+
+		modules = append(modules, m)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return modules, nil
+}
@@ -0,0 +1,62 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModules(t *testing.T) {
+	modules, err := getProcFixtures(t).Modules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Module{
+		{Name: "nvidia_drm", Size: 73728, RefCount: 4, State: "Live", Address: 0xffffffffc1a2e000, Taints: "POE"},
+		{Name: "nvidia", Size: 56115200, RefCount: 45, UsedBy: []string{"nvidia_drm", "nvidia_modeset"}, State: "Live", Address: 0xffffffffc0c00000, Taints: "POE"},
+		{Name: "e1000e", Size: 331776, RefCount: 0, State: "Live", Address: 0xffffffffc0a5c000},
+		{Name: "video", Size: 65536, RefCount: 2, UsedBy: []string{"thinkpad_acpi", "i915"}, State: "Live"},
+		{Name: "dm_mod", Size: 184320, RefCount: 3, UsedBy: []string{"[permanent]"}, State: "Live", Address: 0xffffffffc0b10000},
+		{Name: "zfs", Size: 4071424, RefCount: 1, State: "Loading", Address: 0xffffffffc1400000, Taints: "POE"},
+	}
+	if diff := cmp.Diff(want, modules); diff != "" {
+		t.Errorf("unexpected modules (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseModulesInvalid(t *testing.T) {
+	for _, testdata := range []string{
+		"nvidia 56115200 45 - Live\n",
+		"nvidia large 45 - Live 0xffffffffc0c00000\n",
+		"nvidia 56115200 45 - Live ffffffffzzc00000\n",
+	} {
+		if _, err := parseModules(strings.NewReader(testdata)); err == nil {
+			t.Errorf("expected error parsing %q", testdata)
+		}
+	}
+}
+
+func TestParseModulesNoUnload(t *testing.T) {
+	modules, err := parseModules(strings.NewReader("e1000e 331776 - - Live 0x0000000000000000\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(-1), modules[0].RefCount; want != got {
+		t.Errorf("want RefCount %d, got %d", want, got)
+	}
+}
@@ -348,6 +348,53 @@ func TestFSMountInfo(t *testing.T) {
 				"seclabel": "",
 			},
 		},
+		{
+			MountID:        1200,
+			ParentID:       21,
+			MajorMinorVer:  "252:0",
+			Root:           "/",
+			MountPoint:     "/data",
+			Options:        map[string]string{"relatime": "", "rw": ""},
+			OptionalFields: map[string]string{"shared": "200"},
+			FSType:         "xfs",
+			Source:         "/dev/mapper/vg0--lv_root",
+			SuperOptions: map[string]string{
+				"attr2":   "",
+				"inode64": "",
+				"noquota": "",
+				"rw":      "",
+			},
+		},
+		{
+			MountID:        1201,
+			ParentID:       21,
+			MajorMinorVer:  "0:50",
+			Root:           "/srv",
+			MountPoint:     "/srv/bind",
+			Options:        map[string]string{"relatime": "", "rw": ""},
+			OptionalFields: map[string]string{"shared": "201"},
+			FSType:         "btrfs",
+			Source:         "/dev/sdb",
+			SuperOptions: map[string]string{
+				"rw":          "",
+				"space_cache": "",
+			},
+		},
+		{
+			MountID:        1202,
+			ParentID:       21,
+			MajorMinorVer:  "0:51",
+			Root:           "/",
+			MountPoint:     `/mnt/backup\040disk`,
+			Options:        map[string]string{"relatime": "", "rw": ""},
+			OptionalFields: map[string]string{"shared": "202"},
+			FSType:         "btrfs",
+			Source:         "/dev/sdb",
+			SuperOptions: map[string]string{
+				"rw":          "",
+				"space_cache": "",
+			},
+		},
 	}
 
 	got, err := fs.GetMounts()
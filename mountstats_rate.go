@@ -0,0 +1,187 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// A NFSMountRate contains statistics of an NFS mount derived from two
+// snapshots of its /proc/[pid]/mountstats entry, similar to those reported
+// by nfsiostat.
+type NFSMountRate struct {
+	// Name of the device.
+	Device string
+	// The mount point of the device.
+	Mount string
+	// The time elapsed between the two snapshots, based on the age of the
+	// mount.
+	Interval time.Duration
+	// Statistics broken down by filesystem operation.
+	Operations []NFSOperationRate
+}
+
+// A NFSOperationRate contains statistics for a single operation over the
+// interval between two snapshots.
+type NFSOperationRate struct {
+	// The name of the operation.
+	Operation string
+	// Number of requests performed for this operation.
+	Requests uint64
+	// Number of requests performed per second.
+	OpsPerSecond float64
+	// Number of bytes sent and received per second, including RPC headers
+	// and payload.
+	BytesSentPerSecond     float64
+	BytesReceivedPerSecond float64
+	// Number of retransmissions, i.e. transmissions beyond the first one of
+	// each request.
+	Retransmissions uint64
+	// Ratio of retransmissions to requests.
+	RetransmissionRatio float64
+	// Average time a request spent queued for transmission.
+	AverageQueueTime time.Duration
+	// Average time it took to get a reply after a request was transmitted.
+	AverageRTT time.Duration
+	// Average time from when a request was enqueued to when it was
+	// completely handled.
+	AverageExecuteTime time.Duration
+	// Number of requests that completed with an error.
+	Errors uint64
+}
+
+// NFSRateSince computes the statistics of an NFS mount over the interval
+// between the prev snapshot of the mount and m. It returns an error if either
+// mount is not an NFS mount, if they are not the same mount or if the
+// counters of m are lower than those of prev, e.g. because the filesystem
+// was remounted in between.
+func (m *Mount) NFSRateSince(prev *Mount) (*NFSMountRate, error) {
+	if m.Device != prev.Device || m.Mount != prev.Mount {
+		return nil, fmt.Errorf("mismatched mounts %s on %s and %s on %s", m.Device, m.Mount, prev.Device, prev.Mount)
+	}
+	cur, ok := m.Stats.(*MountStatsNFS)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an NFS mount", m.Mount)
+	}
+	old, ok := prev.Stats.(*MountStatsNFS)
+	if !ok {
+		return nil, fmt.Errorf("previous snapshot of %s is not an NFS mount", m.Mount)
+	}
+	if cur.Age <= old.Age {
+		return nil, errors.New("mount age did not increase between snapshots")
+	}
+
+	interval := cur.Age - old.Age
+	rate := &NFSMountRate{
+		Device:     m.Device,
+		Mount:      m.Mount,
+		Interval:   interval,
+		Operations: make([]NFSOperationRate, 0, len(cur.Operations)),
+	}
+
+	prevOps := make(map[string]NFSOperationStats, len(old.Operations))
+	for _, op := range old.Operations {
+		prevOps[op.Operation] = op
+	}
+	for _, op := range cur.Operations {
+		r, err := nfsOperationRate(prevOps[op.Operation], op, interval)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Mount, err)
+		}
+		rate.Operations = append(rate.Operations, r)
+	}
+
+	return rate, nil
+}
+
+// NFSMountRates computes the statistics of every NFS mount found in both the
+// prev and cur snapshots, as returned by Proc.MountStats. Mounts which cannot
+// be compared, e.g. because they were mounted or remounted between the
+// snapshots, are skipped.
+func NFSMountRates(prev, cur []*Mount) []NFSMountRate {
+	type key struct{ device, mount string }
+	prevMounts := make(map[key]*Mount, len(prev))
+	for _, m := range prev {
+		prevMounts[key{m.Device, m.Mount}] = m
+	}
+
+	var rates []NFSMountRate
+	for _, m := range cur {
+		p, ok := prevMounts[key{m.Device, m.Mount}]
+		if !ok {
+			continue
+		}
+		rate, err := m.NFSRateSince(p)
+		if err != nil {
+			continue
+		}
+		rates = append(rates, *rate)
+	}
+
+	return rates
+}
+
+// nfsOperationRate computes the statistics of a single operation from the
+// difference between the prev and cur counters.
+func nfsOperationRate(prev, cur NFSOperationStats, interval time.Duration) (NFSOperationRate, error) {
+	var err error
+	delta := func(c, p uint64) uint64 {
+		if c < p {
+			err = fmt.Errorf("counters of operation %s decreased between snapshots", cur.Operation)
+			return 0
+		}
+		return c - p
+	}
+
+	requests := delta(cur.Requests, prev.Requests)
+	transmissions := delta(cur.Transmissions, prev.Transmissions)
+	sent := delta(cur.BytesSent, prev.BytesSent)
+	received := delta(cur.BytesReceived, prev.BytesReceived)
+	queue := delta(cur.CumulativeQueueMilliseconds, prev.CumulativeQueueMilliseconds)
+	rtt := delta(cur.CumulativeTotalResponseMilliseconds, prev.CumulativeTotalResponseMilliseconds)
+	execute := delta(cur.CumulativeTotalRequestMilliseconds, prev.CumulativeTotalRequestMilliseconds)
+	errs := delta(cur.Errors, prev.Errors)
+	if err != nil {
+		return NFSOperationRate{}, err
+	}
+
+	seconds := interval.Seconds()
+	rate := NFSOperationRate{
+		Operation:              cur.Operation,
+		Requests:               requests,
+		OpsPerSecond:           float64(requests) / seconds,
+		BytesSentPerSecond:     float64(sent) / seconds,
+		BytesReceivedPerSecond: float64(received) / seconds,
+		Errors:                 errs,
+	}
+	if transmissions > requests {
+		rate.Retransmissions = transmissions - requests
+	}
+	if requests > 0 {
+		rate.RetransmissionRatio = float64(rate.Retransmissions) / float64(requests)
+		rate.AverageQueueTime = averageMilliseconds(queue, requests)
+		rate.AverageRTT = averageMilliseconds(rtt, requests)
+		rate.AverageExecuteTime = averageMilliseconds(execute, requests)
+	}
+
+	return rate, nil
+}
+
+// averageMilliseconds returns the average duration of n events which took
+// total milliseconds.
+func averageMilliseconds(total, n uint64) time.Duration {
+	return time.Duration(float64(total) / float64(n) * float64(time.Millisecond))
+}
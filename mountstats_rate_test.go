@@ -0,0 +1,188 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNFSRateSince(t *testing.T) {
+	nfsMount := func(age time.Duration, ops ...NFSOperationStats) *Mount {
+		return &Mount{
+			Device: "192.168.1.1:/srv/test",
+			Mount:  "/mnt/nfs",
+			Type:   "nfs4",
+			Stats:  &MountStatsNFS{StatVersion: "1.1", Age: age, Operations: ops},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		prev    *Mount
+		cur     *Mount
+		rate    *NFSMountRate
+		invalid bool
+	}{
+		{
+			name:    "not an NFS mount",
+			prev:    &Mount{Device: "/dev/sda1", Mount: "/", Type: "ext4"},
+			cur:     &Mount{Device: "/dev/sda1", Mount: "/", Type: "ext4"},
+			invalid: true,
+		},
+		{
+			name:    "different mounts",
+			prev:    &Mount{Device: "192.168.1.1:/srv/other", Mount: "/mnt/other"},
+			cur:     nfsMount(20 * time.Second),
+			invalid: true,
+		},
+		{
+			name:    "age did not increase",
+			prev:    nfsMount(20 * time.Second),
+			cur:     nfsMount(10 * time.Second),
+			invalid: true,
+		},
+		{
+			name:    "counters decreased",
+			prev:    nfsMount(10*time.Second, NFSOperationStats{Operation: "READ", Requests: 100}),
+			cur:     nfsMount(20*time.Second, NFSOperationStats{Operation: "READ", Requests: 10}),
+			invalid: true,
+		},
+		{
+			name: "good snapshots",
+			prev: nfsMount(10*time.Second,
+				NFSOperationStats{
+					Operation:                           "READ",
+					Requests:                            1000,
+					Transmissions:                       1000,
+					BytesSent:                           128000,
+					BytesReceived:                       4096000,
+					CumulativeQueueMilliseconds:         100,
+					CumulativeTotalResponseMilliseconds: 2000,
+					CumulativeTotalRequestMilliseconds:  2500,
+				},
+				NFSOperationStats{Operation: "WRITE", Requests: 10, Transmissions: 10},
+			),
+			cur: nfsMount(20*time.Second,
+				NFSOperationStats{
+					Operation:                           "READ",
+					Requests:                            1400,
+					Transmissions:                       1410,
+					BytesSent:                           179200,
+					BytesReceived:                       5734400,
+					CumulativeQueueMilliseconds:         120,
+					CumulativeTotalResponseMilliseconds: 2600,
+					CumulativeTotalRequestMilliseconds:  3300,
+					Errors:                              2,
+				},
+				NFSOperationStats{Operation: "WRITE", Requests: 10, Transmissions: 10},
+				// Operations missing from the previous snapshot start at zero.
+				NFSOperationStats{Operation: "COMMIT", Requests: 5, Transmissions: 5, CumulativeTotalResponseMilliseconds: 3},
+			),
+			rate: &NFSMountRate{
+				Device:   "192.168.1.1:/srv/test",
+				Mount:    "/mnt/nfs",
+				Interval: 10 * time.Second,
+				Operations: []NFSOperationRate{
+					{
+						Operation:              "READ",
+						Requests:               400,
+						OpsPerSecond:           40,
+						BytesSentPerSecond:     5120,
+						BytesReceivedPerSecond: 163840,
+						Retransmissions:        10,
+						RetransmissionRatio:    0.025,
+						AverageQueueTime:       50 * time.Microsecond,
+						AverageRTT:             1500 * time.Microsecond,
+						AverageExecuteTime:     2 * time.Millisecond,
+						Errors:                 2,
+					},
+					{Operation: "WRITE"},
+					{
+						Operation:    "COMMIT",
+						Requests:     5,
+						OpsPerSecond: 0.5,
+						AverageRTT:   600 * time.Microsecond,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := tt.cur.NFSRateSince(tt.prev)
+
+			if tt.invalid && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.invalid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.rate, rate); diff != "" {
+				t.Fatalf("unexpected NFS mount rate (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNFSMountRates(t *testing.T) {
+	prev := []*Mount{
+		{Device: "rootfs", Mount: "/", Type: "rootfs"},
+		{
+			Device: "192.168.1.1:/srv/test",
+			Mount:  "/mnt/nfs",
+			Type:   "nfs4",
+			Stats: &MountStatsNFS{
+				Age:        time.Minute,
+				Operations: []NFSOperationStats{{Operation: "GETATTR", Requests: 60}},
+			},
+		},
+	}
+	cur := []*Mount{
+		{Device: "rootfs", Mount: "/", Type: "rootfs"},
+		{
+			Device: "192.168.1.1:/srv/test",
+			Mount:  "/mnt/nfs",
+			Type:   "nfs4",
+			Stats: &MountStatsNFS{
+				Age:        2 * time.Minute,
+				Operations: []NFSOperationStats{{Operation: "GETATTR", Requests: 180}},
+			},
+		},
+		{
+			Device: "192.168.1.2:/srv/new",
+			Mount:  "/mnt/new",
+			Type:   "nfs4",
+			Stats:  &MountStatsNFS{Age: time.Second},
+		},
+	}
+
+	want := []NFSMountRate{
+		{
+			Device:   "192.168.1.1:/srv/test",
+			Mount:    "/mnt/nfs",
+			Interval: time.Minute,
+			Operations: []NFSOperationRate{
+				{Operation: "GETATTR", Requests: 120, OpsPerSecond: 2},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, NFSMountRates(prev, cur)); diff != "" {
+		t.Fatalf("unexpected NFS mount rates (-want +got):\n%s", diff)
+	}
+}
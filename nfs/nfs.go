@@ -11,15 +11,18 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package nfs implements parsing of /proc/net/rpc/nfsd.
+// Package nfs implements parsing of /proc/net/rpc and /proc/fs/nfsd.
 // Fields are documented in https://www.svennd.be/nfsd-stats-explained-procnetrpcnfsd/
 package nfs
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/prometheus/procfs/internal/fs"
+	"github.com/prometheus/procfs/internal/util"
 )
 
 // ReplyCache models the "rc" line.
@@ -278,6 +281,154 @@ type ServerRPCStats struct {
 	WdelegGetattr  uint64
 }
 
+// ServerPoolStats models a line of /proc/fs/nfsd/pool_stats, describing
+// one pool of NFS daemon threads.
+type ServerPoolStats struct {
+	Pool            uint64
+	PacketsArrived  uint64
+	SocketsEnqueued uint64
+	ThreadsWoken    uint64
+	ThreadsTimedOut uint64
+}
+
+// ServerReplyCacheStats models /proc/fs/nfsd/reply_cache_stats, the state and
+// hit/miss counters of the duplicate request cache of the NFS daemon.
+type ServerReplyCacheStats struct {
+	MaxEntries  uint64
+	NumEntries  uint64
+	HashBuckets uint64
+	// MemUsage is the memory used by the cache entries, in bytes.
+	MemUsage      uint64
+	Hits          uint64
+	Misses        uint64
+	NotCached     uint64
+	PayloadMisses uint64
+	// LongestChainLen is the length of the longest hash chain seen, and
+	// CacheSizeAtLongest the number of entries at that time.
+	LongestChainLen    uint64
+	CacheSizeAtLongest uint64
+}
+
+// ServerClient models an NFSv4 client of the NFS daemon, as found in
+// /proc/fs/nfsd/clients/<id>.
+type ServerClient struct {
+	// ID is the name of the client directory.
+	ID                   string
+	ClientID             string
+	Address              string
+	Status               string
+	SecondsFromLastRenew uint64
+	Name                 string
+	MinorVersion         uint64
+	ImplementationDomain string
+	ImplementationName   string
+	CallbackState        string
+	CallbackAddress      string
+	States               []ServerClientState
+}
+
+// StateCounts returns the number of states held by the client keyed by their
+// type, e.g. "open", "lock", "deleg" or "layout".
+func (c ServerClient) StateCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, s := range c.States {
+		counts[s.Type]++
+	}
+	return counts
+}
+
+// ServerClientState models an NFSv4 state, e.g. an open, a lock or a
+// delegation, held by a client.
+type ServerClientState struct {
+	StateID    string
+	Type       string
+	Access     string
+	Deny       string
+	Superblock string
+	Owner      string
+	Filename   string
+	// Flags are the fields without a value, e.g. "admin-revoked" on kernel
+	// 6.9+ for state revoked by the administrator.
+	Flags []string
+}
+
+// ServerExport models a filesystem exported by the NFS daemon, as found in
+// /proc/fs/nfsd/exports.
+type ServerExport struct {
+	Path    string
+	Clients []ServerExportClient
+}
+
+// ServerExportClient models the clients an export is shared with and its
+// export options.
+type ServerExportClient struct {
+	Client  string
+	Options []string
+}
+
+// ServerConfig models the configuration of the NFS daemon, as found in
+// /proc/fs/nfsd.
+type ServerConfig struct {
+	// Threads is the total number of NFS daemon threads.
+	Threads uint64
+	// PoolThreads is the number of threads in each pool.
+	PoolThreads []uint64
+	// MaxBlockSize is the maximum size in bytes of a read or write request.
+	MaxBlockSize uint64
+	// Versions maps NFS versions, e.g. "3" or "4.2", to their enabled state.
+	Versions map[string]bool
+	// Transports are the transports the NFS daemon listens on.
+	Transports []ServerTransport
+	// LeaseTimeSeconds and GraceTimeSeconds are the NFSv4 lease and grace
+	// periods, if NFSv4 support is available.
+	LeaseTimeSeconds *uint64
+	GraceTimeSeconds *uint64
+}
+
+// ServerTransport models a line of /proc/fs/nfsd/portlist.
+//
+// The kernel keeps no per-transport statistics for the NFS daemon. Statistics
+// of the transports of NFS client mounts are found in the "xprt:" lines of
+// /proc/<pid>/mountstats, see procfs.MountStatsNFS.Transport.
+type ServerTransport struct {
+	Protocol string
+	Port     uint64
+}
+
+// RPCCache models a SunRPC cache, e.g. auth.unix.ip, nfsd.fh or nfsd.export,
+// as found in /proc/net/rpc/<name>.
+//
+// The kernel does not count hits or misses of these caches. The hit and miss
+// counters of the NFS daemon are those of its duplicate request cache, see
+// ServerReplyCacheStats and ServerRPCStats.ReplyCache.
+type RPCCache struct {
+	Name string
+	// FlushTime is the time of the last flush of the cache in seconds since
+	// the epoch.
+	FlushTime uint64
+	Entries   []RPCCacheEntry
+}
+
+// RPCCacheEntry models an entry of a SunRPC cache. The meaning of the fields
+// depends on the cache and is described by the header of its content file.
+type RPCCacheEntry struct {
+	Fields []string
+	// Expired is true for entries which are expired or not yet valid.
+	Expired bool
+}
+
+// ValidEntries returns the number of entries of the cache which have not
+// expired.
+func (c RPCCache) ValidEntries() int {
+	var n int
+	for _, e := range c.Entries {
+		if !e.Expired {
+			n++
+		}
+	}
+	return n
+}
+
 // FS represents the pseudo-filesystem proc, which provides an interface to
 // kernel data structures.
 type FS struct {
@@ -326,3 +477,176 @@ func (fs FS) ServerRPCStats() (*ServerRPCStats, error) {
 
 	return ParseServerRPCStats(f)
 }
+
+// ServerPoolStats retrieves NFS daemon thread pool statistics
+// from proc/fs/nfsd/pool_stats.
+func (fs FS) ServerPoolStats() ([]ServerPoolStats, error) {
+	f, err := os.Open(fs.proc.Path("fs/nfsd/pool_stats"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseServerPoolStats(f)
+}
+
+// ServerReplyCacheStats retrieves the NFS daemon duplicate request cache
+// statistics from proc/fs/nfsd/reply_cache_stats.
+func (fs FS) ServerReplyCacheStats() (*ServerReplyCacheStats, error) {
+	f, err := os.Open(fs.proc.Path("fs/nfsd/reply_cache_stats"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseServerReplyCacheStats(f)
+}
+
+// ServerClients retrieves the NFSv4 clients of the NFS daemon and the state
+// they hold from proc/fs/nfsd/clients. Only available on kernel 5.3+.
+func (fs FS) ServerClients() ([]ServerClient, error) {
+	entries, err := os.ReadDir(fs.proc.Path("fs/nfsd/clients"))
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []ServerClient
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		client, err := fs.serverClient(entry.Name())
+		if err != nil {
+			// Clients may go away while they are being read.
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		clients = append(clients, *client)
+	}
+
+	return clients, nil
+}
+
+func (fs FS) serverClient(id string) (*ServerClient, error) {
+	dir := fs.proc.Path("fs/nfsd/clients", id)
+
+	f, err := os.Open(filepath.Join(dir, "info"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	client, err := ParseServerClientInfo(f)
+	if err != nil {
+		return nil, err
+	}
+	client.ID = id
+
+	s, err := os.Open(filepath.Join(dir, "states"))
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	if client.States, err = ParseServerClientStates(s); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// ServerExports retrieves the filesystems exported by the NFS daemon
+// from proc/fs/nfsd/exports.
+func (fs FS) ServerExports() ([]ServerExport, error) {
+	f, err := os.Open(fs.proc.Path("fs/nfsd/exports"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseServerExports(f)
+}
+
+// ServerConfig retrieves the configuration of the NFS daemon
+// from proc/fs/nfsd.
+func (fs FS) ServerConfig() (*ServerConfig, error) {
+	var config ServerConfig
+
+	for file, p := range map[string]*uint64{
+		"threads":        &config.Threads,
+		"max_block_size": &config.MaxBlockSize,
+	} {
+		val, err := util.ReadUintFromFile(fs.proc.Path("fs/nfsd", file))
+		if err != nil {
+			return nil, err
+		}
+		*p = val
+	}
+
+	// NFSv4 specific files are absent if the kernel lacks NFSv4 support.
+	for file, p := range map[string]**uint64{
+		"nfsv4leasetime": &config.LeaseTimeSeconds,
+		"nfsv4gracetime": &config.GraceTimeSeconds,
+	} {
+		val, err := util.ReadUintFromFile(fs.proc.Path("fs/nfsd", file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		*p = &val
+	}
+
+	data, err := util.SysReadFile(fs.proc.Path("fs/nfsd/pool_threads"))
+	if err != nil {
+		return nil, err
+	}
+	if config.PoolThreads, err = util.ParseUint64s(strings.Fields(data)); err != nil {
+		return nil, fmt.Errorf("error parsing NFSd pool_threads %q: %w", data, err)
+	}
+
+	data, err = util.SysReadFile(fs.proc.Path("fs/nfsd/versions"))
+	if err != nil {
+		return nil, err
+	}
+	if config.Versions, err = parseServerVersions(data); err != nil {
+		return nil, err
+	}
+
+	b, err := util.ReadFileNoStat(fs.proc.Path("fs/nfsd/portlist"))
+	if err != nil {
+		return nil, err
+	}
+	if config.Transports, err = parseServerTransports(string(b)); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// RPCCache retrieves the named SunRPC cache, e.g. auth.unix.ip, nfsd.fh or
+// nfsd.export, from proc/net/rpc/<name>. The channel file of the cache is not
+// read, as reading it consumes the upcalls destined for the user space
+// daemon.
+func (fs FS) RPCCache(name string) (*RPCCache, error) {
+	flush, err := util.ReadUintFromFile(fs.proc.Path("net/rpc", name, "flush"))
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(fs.proc.Path("net/rpc", name, "content"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := ParseRPCCacheContent(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RPCCache{Name: name, FlushTime: flush, Entries: entries}, nil
+}
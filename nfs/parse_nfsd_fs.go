@@ -0,0 +1,345 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// ParseServerPoolStats returns the thread pool statistics read from
+// /proc/fs/nfsd/pool_stats.
+func ParseServerPoolStats(r io.Reader) ([]ServerPoolStats, error) {
+	var stats []ServerPoolStats
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Fields(line)
+		// require <pool> <packets-arrived> <sockets-enqueued> <threads-woken> <threads-timedout>
+		if len(parts) < 5 {
+			return nil, fmt.Errorf("invalid NFSd pool_stats line %q", line)
+		}
+		values, err := util.ParseUint64s(parts[:5])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing NFSd pool_stats line: %w", err)
+		}
+		stats = append(stats, ServerPoolStats{
+			Pool:            values[0],
+			PacketsArrived:  values[1],
+			SocketsEnqueued: values[2],
+			ThreadsWoken:    values[3],
+			ThreadsTimedOut: values[4],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning NFSd pool_stats file: %w", err)
+	}
+
+	return stats, nil
+}
+
+// ParseServerReplyCacheStats returns the duplicate request cache statistics
+// read from /proc/fs/nfsd/reply_cache_stats. Unknown keys are ignored.
+func ParseServerReplyCacheStats(r io.Reader) (*ServerReplyCacheStats, error) {
+	stats := &ServerReplyCacheStats{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid NFSd reply_cache_stats line %q", line)
+		}
+
+		var p *uint64
+		switch key {
+		case "max entries":
+			p = &stats.MaxEntries
+		case "num entries":
+			p = &stats.NumEntries
+		case "hash buckets":
+			p = &stats.HashBuckets
+		case "mem usage":
+			p = &stats.MemUsage
+		case "cache hits":
+			p = &stats.Hits
+		case "cache misses":
+			p = &stats.Misses
+		case "not cached":
+			p = &stats.NotCached
+		case "payload misses":
+			p = &stats.PayloadMisses
+		case "longest chain len":
+			p = &stats.LongestChainLen
+		case "cachesize at longest":
+			p = &stats.CacheSizeAtLongest
+		default:
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing NFSd reply_cache_stats line %q: %w", line, err)
+		}
+		*p = v
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning NFSd reply_cache_stats file: %w", err)
+	}
+
+	return stats, nil
+}
+
+// ParseServerClientInfo returns the NFSv4 client information read from
+// /proc/fs/nfsd/clients/<id>/info. The ID and States of the returned client
+// are not set.
+func ParseServerClientInfo(r io.Reader) (*ServerClient, error) {
+	client := &ServerClient{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("invalid NFSd client info line %q", line)
+		}
+		value = unquote(value)
+
+		var err error
+		switch key {
+		case "clientid":
+			client.ClientID = value
+		case "address":
+			client.Address = value
+		case "status":
+			client.Status = value
+		case "seconds from last renew":
+			client.SecondsFromLastRenew, err = strconv.ParseUint(value, 10, 64)
+		case "name":
+			client.Name = value
+		case "minor version":
+			client.MinorVersion, err = strconv.ParseUint(value, 10, 64)
+		case "Implementation domain":
+			client.ImplementationDomain = value
+		case "Implementation name":
+			client.ImplementationName = value
+		case "callback state":
+			client.CallbackState = value
+		case "callback address":
+			client.CallbackAddress = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing NFSd client info line %q: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning NFSd client info file: %w", err)
+	}
+
+	return client, nil
+}
+
+// ParseServerClientStates returns the NFSv4 state held by a client read from
+// /proc/fs/nfsd/clients/<id>/states.
+func ParseServerClientStates(r io.Reader) ([]ServerClientState, error) {
+	var states []ServerClientState
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// - 0x00000001...: { type: open, access: rw, deny: --, ... }
+		stateID, body, ok := strings.Cut(strings.TrimPrefix(line, "- "), ": {")
+		if !ok || !strings.HasSuffix(body, "}") {
+			return nil, fmt.Errorf("invalid NFSd client states line %q", line)
+		}
+
+		state := ServerClientState{StateID: stateID}
+		for _, field := range splitQuoted(strings.TrimSuffix(body, "}")) {
+			key, value, ok := strings.Cut(field, ": ")
+			if !ok {
+				state.Flags = append(state.Flags, field)
+				continue
+			}
+			value = unquote(value)
+
+			switch key {
+			case "type":
+				state.Type = value
+			case "access":
+				state.Access = value
+			case "deny":
+				state.Deny = value
+			case "superblock":
+				state.Superblock = value
+			case "owner":
+				state.Owner = value
+			case "filename":
+				state.Filename = value
+			}
+		}
+		states = append(states, state)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning NFSd client states file: %w", err)
+	}
+
+	return states, nil
+}
+
+// ParseServerExports returns the exported filesystems read from
+// /proc/fs/nfsd/exports.
+func ParseServerExports(r io.Reader) ([]ServerExport, error) {
+	var exports []ServerExport
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Fields(line)
+		// require <path> <client>(<options>) ...
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid NFSd exports line %q", line)
+		}
+
+		export := ServerExport{Path: unescapeOctal(parts[0])}
+		for _, part := range parts[1:] {
+			client, options, ok := strings.Cut(part, "(")
+			if !ok || !strings.HasSuffix(options, ")") {
+				return nil, fmt.Errorf("invalid NFSd exports client %q", part)
+			}
+			export.Clients = append(export.Clients, ServerExportClient{
+				Client:  unescapeOctal(client),
+				Options: strings.Split(strings.TrimSuffix(options, ")"), ","),
+			})
+		}
+		exports = append(exports, export)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning NFSd exports file: %w", err)
+	}
+
+	return exports, nil
+}
+
+// splitQuoted splits s on commas that are not within double quotes and trims
+// the space around each field.
+func splitQuoted(s string) []string {
+	var (
+		fields []string
+		quoted bool
+		start  int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				fields = append(fields, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		fields = append(fields, last)
+	}
+	return fields
+}
+
+// unquote removes the double quotes around s and decodes its escape
+// sequences. Unquoted strings are returned as is.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s[1 : len(s)-1]
+}
+
+// unescapeOctal decodes the \ooo octal escapes the kernel uses for white space
+// and special characters in paths.
+func unescapeOctal(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseServerVersions parses the contents of /proc/fs/nfsd/versions, e.g.
+// "-2 +3 +4 +4.1 +4.2", into a map of enabled state keyed by version.
+func parseServerVersions(s string) (map[string]bool, error) {
+	versions := make(map[string]bool)
+	for _, v := range strings.Fields(s) {
+		switch v[0] {
+		case '+':
+			versions[v[1:]] = true
+		case '-':
+			versions[v[1:]] = false
+		default:
+			return nil, fmt.Errorf("invalid NFSd version %q", v)
+		}
+	}
+	return versions, nil
+}
+
+// parseServerTransports parses the contents of /proc/fs/nfsd/portlist, one
+// "<protocol> <port>" pair per line.
+func parseServerTransports(s string) ([]ServerTransport, error) {
+	var transports []ServerTransport
+	for _, line := range strings.Split(s, "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid NFSd portlist line %q", line)
+		}
+		port, err := strconv.ParseUint(parts[1], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing NFSd portlist line %q: %w", line, err)
+		}
+		transports = append(transports, ServerTransport{Protocol: parts[0], Port: port})
+	}
+	return transports, nil
+}
@@ -0,0 +1,403 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nfs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/prometheus/procfs/nfs"
+)
+
+func TestParseServerPoolStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		stats   []nfs.ServerPoolStats
+		invalid bool
+	}{
+		{
+			name:    "invalid file",
+			content: "0 1 2 3",
+			invalid: true,
+		}, {
+			name:    "invalid value",
+			content: "0 1 2 3 x",
+			invalid: true,
+		}, {
+			name: "extra columns",
+			content: `# pool packets-arrived sockets-enqueued threads-woken threads-timedout extra
+0 1894321 2087 1892234 12 42
+`,
+			stats: []nfs.ServerPoolStats{
+				{Pool: 0, PacketsArrived: 1894321, SocketsEnqueued: 2087, ThreadsWoken: 1892234, ThreadsTimedOut: 12},
+			},
+		}, {
+			name: "good file",
+			content: `# pool packets-arrived sockets-enqueued threads-woken threads-timedout
+0 1894321 2087 1892234 12
+1 1785119 3011 1782108 7
+`,
+			stats: []nfs.ServerPoolStats{
+				{Pool: 0, PacketsArrived: 1894321, SocketsEnqueued: 2087, ThreadsWoken: 1892234, ThreadsTimedOut: 12},
+				{Pool: 1, PacketsArrived: 1785119, SocketsEnqueued: 3011, ThreadsWoken: 1782108, ThreadsTimedOut: 7},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := nfs.ParseServerPoolStats(strings.NewReader(tt.content))
+
+			if tt.invalid && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.invalid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.stats, stats); diff != "" {
+				t.Fatalf("unexpected pool stats (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseServerReplyCacheStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		stats   *nfs.ServerReplyCacheStats
+		invalid bool
+	}{
+		{
+			name:    "invalid line",
+			content: "max entries 1024",
+			invalid: true,
+		}, {
+			name:    "invalid value",
+			content: "cache hits:            x",
+			invalid: true,
+		}, {
+			name: "good file",
+			content: `max entries:           130620
+num entries:           1186
+hash buckets:          2048
+mem usage:             220304
+cache hits:            2781
+cache misses:          1876542
+not cached:            4213305
+payload misses:        0
+longest chain len:     4
+cachesize at longest:  1101
+`,
+			stats: &nfs.ServerReplyCacheStats{
+				MaxEntries:         130620,
+				NumEntries:         1186,
+				HashBuckets:        2048,
+				MemUsage:           220304,
+				Hits:               2781,
+				Misses:             1876542,
+				NotCached:          4213305,
+				PayloadMisses:      0,
+				LongestChainLen:    4,
+				CacheSizeAtLongest: 1101,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := nfs.ParseServerReplyCacheStats(strings.NewReader(tt.content))
+
+			if tt.invalid && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.invalid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.stats, stats); diff != "" {
+				t.Fatalf("unexpected reply cache stats (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseServerClientStates(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		states  []nfs.ServerClientState
+		invalid bool
+	}{
+		{
+			name:    "invalid file",
+			content: "invalid",
+			invalid: true,
+		}, {
+			name:    "unterminated state",
+			content: "- 0x0000000a: { type: open",
+			invalid: true,
+		}, {
+			name: "good file",
+			content: `- 0x00000001650f4c5c6d0e0e4f0000000a: { type: open, access: rw, deny: --, superblock: "fd:00:1179", owner: "open id:\x00\x01", filename: "/srv/nfs/data.db" }
+- 0x00000001650f4c5c6d0e0e4f0000000c: { type: deleg, access: r, superblock: "fd:00:1203", filename: "/srv/nfs/config, old.yaml" }
+- 0x00000001650f4c5c6d0e0e4f0000000d: { type: layout, superblock: "fd:00:1203", filename: "/srv/nfs/image", layout: pnfs-scsi }
+- 0x00000001650f4c5c6d0e0e4f0000000e: { type: open, access: r, deny: --, superblock: "fd:00:1179", owner: "open id:\x00\x02", filename: "/srv/nfs/old.log", admin-revoked }
+`,
+			states: []nfs.ServerClientState{
+				{
+					StateID:    "0x00000001650f4c5c6d0e0e4f0000000a",
+					Type:       "open",
+					Access:     "rw",
+					Deny:       "--",
+					Superblock: "fd:00:1179",
+					Owner:      "open id:\x00\x01",
+					Filename:   "/srv/nfs/data.db",
+				},
+				{
+					StateID:    "0x00000001650f4c5c6d0e0e4f0000000c",
+					Type:       "deleg",
+					Access:     "r",
+					Superblock: "fd:00:1203",
+					Filename:   "/srv/nfs/config, old.yaml",
+				},
+				{
+					StateID:    "0x00000001650f4c5c6d0e0e4f0000000d",
+					Type:       "layout",
+					Superblock: "fd:00:1203",
+					Filename:   "/srv/nfs/image",
+				},
+				{
+					StateID:    "0x00000001650f4c5c6d0e0e4f0000000e",
+					Type:       "open",
+					Access:     "r",
+					Deny:       "--",
+					Superblock: "fd:00:1179",
+					Owner:      "open id:\x00\x02",
+					Filename:   "/srv/nfs/old.log",
+					Flags:      []string{"admin-revoked"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states, err := nfs.ParseServerClientStates(strings.NewReader(tt.content))
+
+			if tt.invalid && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.invalid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.states, states); diff != "" {
+				t.Fatalf("unexpected client states (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseServerExports(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		exports []nfs.ServerExport
+		invalid bool
+	}{
+		{
+			name:    "missing client",
+			content: "/srv/nfs",
+			invalid: true,
+		}, {
+			name:    "invalid client",
+			content: "/srv/nfs\t*(rw",
+			invalid: true,
+		}, {
+			name: "good file",
+			content: `# Version 1.1
+# Path Client(Flags) # IPs
+/srv/nfs	192.168.1.0/24(rw,sync,sec=1) 10.0.0.1(ro,sec=1)
+/srv/public\040share	*(ro,root_squash)
+`,
+			exports: []nfs.ServerExport{
+				{
+					Path: "/srv/nfs",
+					Clients: []nfs.ServerExportClient{
+						{Client: "192.168.1.0/24", Options: []string{"rw", "sync", "sec=1"}},
+						{Client: "10.0.0.1", Options: []string{"ro", "sec=1"}},
+					},
+				},
+				{
+					Path: "/srv/public share",
+					Clients: []nfs.ServerExportClient{
+						{Client: "*", Options: []string{"ro", "root_squash"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exports, err := nfs.ParseServerExports(strings.NewReader(tt.content))
+
+			if tt.invalid && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.invalid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.exports, exports); diff != "" {
+				t.Fatalf("unexpected exports (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFSServerClients(t *testing.T) {
+	fs, err := nfs.NewFS("testdata/fixtures/proc")
+	if err != nil {
+		t.Fatalf("failed to access proc fs: %v", err)
+	}
+	clients, err := fs.ServerClients()
+	if err != nil {
+		t.Fatalf("failed to read NFSd clients: %v", err)
+	}
+
+	want := []nfs.ServerClient{
+		{
+			ID:                   "3",
+			ClientID:             "0x6d0e0e4f650f4c5c",
+			Address:              "192.168.1.5:883",
+			Status:               "confirmed",
+			SecondsFromLastRenew: 12,
+			Name:                 "Linux NFSv4.2 client-a",
+			MinorVersion:         2,
+			ImplementationDomain: "kernel.org",
+			ImplementationName:   "Linux 6.1.0-18-amd64 #1 SMP PREEMPT_DYNAMIC Debian 6.1.76-1 (2024-02-01) x86_64",
+			CallbackState:        "UP",
+			CallbackAddress:      "192.168.1.5:0",
+			States: []nfs.ServerClientState{
+				{
+					StateID:    "0x00000001650f4c5c6d0e0e4f0000000a",
+					Type:       "open",
+					Access:     "rw",
+					Deny:       "--",
+					Superblock: "fd:00:1179",
+					Owner:      "open id:\x00\x00\x00&\x00\x00\x00\x00\x00\x00\x01\x9c",
+					Filename:   "/srv/nfs/data.db",
+				},
+				{
+					StateID:    "0x00000002650f4c5c6d0e0e4f0000000b",
+					Type:       "lock",
+					Superblock: "fd:00:1179",
+					Owner:      "lock id:\x00\x00\x00&\x00\x00\x00\x00",
+					Filename:   "/srv/nfs/data.db",
+				},
+				{
+					StateID:    "0x00000001650f4c5c6d0e0e4f0000000c",
+					Type:       "deleg",
+					Access:     "r",
+					Superblock: "fd:00:1203",
+					Filename:   "/srv/nfs/config, old.yaml",
+				},
+			},
+		},
+		{
+			ID:                   "4",
+			ClientID:             "0x6d0e0e4f650f4c5d",
+			Address:              "192.168.1.6:790",
+			Status:               "unconfirmed",
+			SecondsFromLastRenew: 85,
+			Name:                 "Linux NFSv4.1 client-b",
+			MinorVersion:         1,
+			CallbackState:        "DOWN",
+			CallbackAddress:      "(einval)",
+		},
+	}
+	if diff := cmp.Diff(want, clients); diff != "" {
+		t.Fatalf("unexpected NFSd clients (-want +got):\n%s", diff)
+	}
+
+	wantCounts := map[string]int{"open": 1, "lock": 1, "deleg": 1}
+	if diff := cmp.Diff(wantCounts, clients[0].StateCounts()); diff != "" {
+		t.Fatalf("unexpected state counts (-want +got):\n%s", diff)
+	}
+}
+
+func TestFSServerPoolStatsReplyCacheAndExports(t *testing.T) {
+	fs, err := nfs.NewFS("testdata/fixtures/proc")
+	if err != nil {
+		t.Fatalf("failed to access proc fs: %v", err)
+	}
+
+	stats, err := fs.ServerPoolStats()
+	if err != nil {
+		t.Fatalf("failed to read NFSd pool stats: %v", err)
+	}
+	if want, have := 2, len(stats); want != have {
+		t.Errorf("want %d pools, have %d", want, have)
+	}
+
+	replyCache, err := fs.ServerReplyCacheStats()
+	if err != nil {
+		t.Fatalf("failed to read NFSd reply cache stats: %v", err)
+	}
+	if want, have := uint64(2781), replyCache.Hits; want != have {
+		t.Errorf("want %d reply cache hits, have %d", want, have)
+	}
+
+	exports, err := fs.ServerExports()
+	if err != nil {
+		t.Fatalf("failed to read NFSd exports: %v", err)
+	}
+	if want, have := "/srv/public share", exports[1].Path; want != have {
+		t.Errorf("want export path %q, have %q", want, have)
+	}
+}
+
+func TestFSServerConfig(t *testing.T) {
+	fs, err := nfs.NewFS("testdata/fixtures/proc")
+	if err != nil {
+		t.Fatalf("failed to access proc fs: %v", err)
+	}
+	config, err := fs.ServerConfig()
+	if err != nil {
+		t.Fatalf("failed to read NFSd config: %v", err)
+	}
+
+	ninety := uint64(90)
+	want := &nfs.ServerConfig{
+		Threads:      16,
+		PoolThreads:  []uint64{8, 8},
+		MaxBlockSize: 1048576,
+		Versions:     map[string]bool{"2": false, "3": true, "4": true, "4.1": true, "4.2": true},
+		Transports: []nfs.ServerTransport{
+			{Protocol: "rdma", Port: 20049},
+			{Protocol: "tcp", Port: 2049},
+		},
+		LeaseTimeSeconds: &ninety,
+		GraceTimeSeconds: &ninety,
+	}
+	if diff := cmp.Diff(want, config); diff != "" {
+		t.Fatalf("unexpected NFSd config (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseRPCCacheContent returns the entries of a SunRPC cache read from
+// /proc/net/rpc/<cache>/content.
+func ParseRPCCacheContent(r io.Reader) ([]RPCCacheEntry, error) {
+	var entries []RPCCacheEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		var expired bool
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "# expiry="):
+			// Debug information, only printed with RPC cache debugging enabled.
+			continue
+		case strings.HasPrefix(line, "# "):
+			// Entries which are expired or not yet valid are commented out.
+			expired = true
+			line = line[2:]
+		case strings.HasPrefix(line, "#"):
+			// Header describing the columns, e.g. "#class IP domain".
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid RPC cache content line %q", scanner.Text())
+		}
+		entries = append(entries, RPCCacheEntry{Fields: fields, Expired: expired})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning RPC cache content file: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,96 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nfs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/prometheus/procfs/nfs"
+)
+
+func TestParseRPCCacheContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		entries []nfs.RPCCacheEntry
+		invalid bool
+	}{
+		{
+			name:    "invalid expired entry",
+			content: "#class IP domain\n# \n",
+			invalid: true,
+		}, {
+			name:    "header only",
+			content: "#path domain(flags)\n",
+		}, {
+			name: "good file",
+			content: `#class IP domain
+nfsd 192.168.1.5 192.168.1.0/24
+# expiry=1729000240 refcnt=2 flags=1
+# nfsd 10.0.0.9 -no-domain-
+`,
+			entries: []nfs.RPCCacheEntry{
+				{Fields: []string{"nfsd", "192.168.1.5", "192.168.1.0/24"}},
+				{Fields: []string{"nfsd", "10.0.0.9", "-no-domain-"}, Expired: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := nfs.ParseRPCCacheContent(strings.NewReader(tt.content))
+
+			if tt.invalid && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.invalid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.entries, entries); diff != "" {
+				t.Fatalf("unexpected cache entries (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFSRPCCache(t *testing.T) {
+	fs, err := nfs.NewFS("testdata/fixtures/proc")
+	if err != nil {
+		t.Fatalf("failed to access proc fs: %v", err)
+	}
+	cache, err := fs.RPCCache("auth.unix.ip")
+	if err != nil {
+		t.Fatalf("failed to read RPC cache: %v", err)
+	}
+
+	want := &nfs.RPCCache{
+		Name:      "auth.unix.ip",
+		FlushTime: 1729000000,
+		Entries: []nfs.RPCCacheEntry{
+			{Fields: []string{"nfsd", "192.168.1.5", "192.168.1.0/24"}},
+			{Fields: []string{"nfsd", "192.168.1.6", "192.168.1.0/24"}},
+			{Fields: []string{"nfsd", "10.0.0.9", "-no-domain-"}, Expired: true},
+		},
+	}
+	if diff := cmp.Diff(want, cache); diff != "" {
+		t.Fatalf("unexpected RPC cache (-want +got):\n%s", diff)
+	}
+	if want, have := 2, cache.ValidEntries(); want != have {
+		t.Errorf("want %d valid entries, have %d", want, have)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// PageTypeInfo is the details parsed from /proc/pagetypeinfo, which breaks
+// down the free blocks of each zone reported in /proc/buddyinfo by migrate
+// type.
+type PageTypeInfo struct {
+	// PageBlockOrder is the order of a page block, the unit in which pages
+	// are grouped by migrate type.
+	PageBlockOrder uint64
+	// PagesPerBlock is the number of pages in a page block.
+	PagesPerBlock uint64
+	Zones         []PageTypeZone
+}
+
+// PageTypeZone contains the free blocks and page blocks of a zone by migrate
+// type, e.g. "Unmovable", "Movable" or "Reclaimable".
+type PageTypeZone struct {
+	Node string
+	Zone string
+	// FreePages maps each migrate type to the number of free blocks of
+	// 2^n*PAGE_SIZE, where n is the array index. The kernel stops counting
+	// at 100000 blocks per order, larger counts are reported as 100000.
+	FreePages map[string][]uint64
+	// Blocks maps each migrate type to the number of page blocks of the
+	// type.
+	Blocks map[string]uint64
+}
+
+// PageTypeInfo reads the pagetypeinfo statistics from the specified `proc`
+// filesystem. Reading /proc/pagetypeinfo requires root privileges.
+func (fs FS) PageTypeInfo() (*PageTypeInfo, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path("pagetypeinfo"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePageTypeInfo(bytes.NewReader(data))
+}
+
+func parsePageTypeInfo(r io.Reader) (*PageTypeInfo, error) {
+	var (
+		info       = PageTypeInfo{}
+		scanner    = bufio.NewScanner(r)
+		zones      = map[[2]string]int{}
+		blockTypes []string
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Fields(line)
+
+		switch {
+		case len(parts) == 0:
+			continue
+		case strings.HasPrefix(line, "Page block order:"):
+			v, err := strconv.ParseUint(parts[len(parts)-1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid page block order %q: %w", ErrFileParse, line, err)
+			}
+			info.PageBlockOrder = v
+		case strings.HasPrefix(line, "Pages per block:"):
+			v, err := strconv.ParseUint(parts[len(parts)-1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid pages per block %q: %w", ErrFileParse, line, err)
+			}
+			info.PagesPerBlock = v
+		case strings.HasPrefix(line, "Number of blocks type"):
+			blockTypes = parts[4:]
+		case strings.HasPrefix(line, "Number of"):
+			// Sections only present with page_owner enabled, e.g.
+			// "Number of mixed blocks".
+			blockTypes = nil
+		case parts[0] != "Node":
+			continue
+		case len(parts) >= 6 && parts[4] == "type":
+			// Node    0, zone      DMA, type    Unmovable      1      0 ...
+			node, zone := strings.TrimSuffix(parts[1], ","), strings.TrimSuffix(parts[3], ",")
+			counts := make([]uint64, len(parts[6:]))
+			for i, c := range parts[6:] {
+				v, err := strconv.ParseUint(strings.TrimPrefix(c, ">"), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid free pages count in %q: %w", ErrFileParse, line, err)
+				}
+				counts[i] = v
+			}
+			i, ok := zones[[2]string{node, zone}]
+			if !ok {
+				i = len(info.Zones)
+				zones[[2]string{node, zone}] = i
+				info.Zones = append(info.Zones, PageTypeZone{
+					Node:      node,
+					Zone:      zone,
+					FreePages: map[string][]uint64{},
+					Blocks:    map[string]uint64{},
+				})
+			}
+			info.Zones[i].FreePages[parts[5]] = counts
+		case blockTypes != nil && len(parts) >= 4:
+			// Node 0, zone      DMA            1            7 ...
+			node, zone := strings.TrimSuffix(parts[1], ","), parts[3]
+			if len(parts[4:]) != len(blockTypes) {
+				return nil, fmt.Errorf("%w: mismatch in number of block types in %q, expected %d", ErrFileParse, line, len(blockTypes))
+			}
+			i, ok := zones[[2]string{node, zone}]
+			if !ok {
+				return nil, fmt.Errorf("%w: block counts for unknown zone in %q", ErrFileParse, line)
+			}
+			for j, c := range parts[4:] {
+				v, err := strconv.ParseUint(c, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid block count in %q: %w", ErrFileParse, line, err)
+				}
+				info.Zones[i].Blocks[blockTypes[j]] = v
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
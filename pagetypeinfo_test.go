@@ -0,0 +1,103 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPageTypeInfo(t *testing.T) {
+	info, err := getProcFixtures(t).PageTypeInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := uint64(9), info.PageBlockOrder; want != got {
+		t.Errorf("want PageBlockOrder %d, got %d", want, got)
+	}
+	if want, got := uint64(512), info.PagesPerBlock; want != got {
+		t.Errorf("want PagesPerBlock %d, got %d", want, got)
+	}
+	if want, got := 3, len(info.Zones); want != got {
+		t.Fatalf("want %d zones, got %d", want, got)
+	}
+
+	zero := make([]uint64, 11)
+	want := PageTypeZone{
+		Node: "0",
+		Zone: "Normal",
+		FreePages: map[string][]uint64{
+			"Unmovable":   {381, 93, 85, 30, 67, 2, 4, 0, 0, 0, 0},
+			"Movable":     {3000, 1000, 100, 1500, 500, 100, 0, 0, 0, 0, 0},
+			"Reclaimable": {1000, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			"HighAtomic":  zero,
+			"Isolate":     zero,
+		},
+		Blocks: map[string]uint64{
+			"Unmovable":   90,
+			"Movable":     7555,
+			"Reclaimable": 131,
+			"HighAtomic":  2,
+			"Isolate":     0,
+		},
+	}
+	if diff := cmp.Diff(want, info.Zones[2]); diff != "" {
+		t.Errorf("unexpected zone (-want +got):\n%s", diff)
+	}
+
+	// The free blocks of all migrate types add up to those in buddyinfo.
+	buddyInfo, err := getProcFixtures(t).BuddyInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, zone := range info.Zones {
+		sums := make([]float64, 11)
+		for _, counts := range zone.FreePages {
+			for o, n := range counts {
+				sums[o] += float64(n)
+			}
+		}
+		if diff := cmp.Diff(buddyInfo[i].Sizes, sums); diff != "" {
+			t.Errorf("zone %s free blocks differ from buddyinfo (-want +got):\n%s", zone.Zone, diff)
+		}
+	}
+}
+
+func TestParsePageTypeInfoOverflow(t *testing.T) {
+	testdata := `Page block order: 9
+Pages per block:  512
+
+Free pages count per migrate type at order       0      1      2
+Node    0, zone   Normal, type      Movable >100000  41234      7
+
+Number of blocks type     Unmovable      Movable
+Node 0, zone   Normal           90         7555
+Node 1, zone   Normal           90         7555
+`
+	_, err := parsePageTypeInfo(strings.NewReader(testdata))
+	if err == nil {
+		t.Fatal("expected error for block counts of unknown zone")
+	}
+
+	info, err := parsePageTypeInfo(strings.NewReader(strings.TrimSuffix(testdata, "Node 1, zone   Normal           90         7555\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]uint64{100000, 41234, 7}, info.Zones[0].FreePages["Movable"]); diff != "" {
+		t.Errorf("unexpected free pages (-want +got):\n%s", diff)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/prometheus/procfs/internal/util"
 )
@@ -45,6 +46,8 @@ type ProcFDInfo struct {
 	Ino string
 	// List of inotify lines (structured) in the fdinfo file (kernel 3.8+ only)
 	InotifyInfos []InotifyInfo
+	// Locks held through the file descriptor (kernel 4.1+ only)
+	Locks []Lock
 }
 
 // FDInfo constructor. On kernels older than 3.8, InotifyInfos will always be empty.
@@ -56,6 +59,7 @@ func (p Proc) FDInfo(fd string) (*ProcFDInfo, error) {
 
 	var text, pos, flags, mntid, ino string
 	var inotify []InotifyInfo
+	var locks []Lock
 
 	scanner := bufio.NewScanner(bytes.NewReader(data))
 	for scanner.Scan() {
@@ -75,6 +79,13 @@ func (p Proc) FDInfo(fd string) (*ProcFDInfo, error) {
 				return nil, err
 			}
 			inotify = append(inotify, *newInotify)
+		case strings.HasPrefix(text, "lock:"):
+			lock, _, err := parseLock(strings.TrimSpace(strings.TrimPrefix(text, "lock:")))
+			if err != nil {
+				return nil, err
+			}
+			lock.Owner = p.fs.lockOwner(lock.PID)
+			locks = append(locks, lock)
 		}
 	}
 
@@ -85,6 +96,7 @@ func (p Proc) FDInfo(fd string) (*ProcFDInfo, error) {
 		MntID:        mntid,
 		Ino:          ino,
 		InotifyInfos: inotify,
+		Locks:        locks,
 	}
 
 	return i, nil
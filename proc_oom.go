@@ -0,0 +1,131 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// ProcOOM contains the OOM killer state of a process.
+//
+// See: https://www.kernel.org/doc/Documentation/filesystems/proc.txt
+type ProcOOM struct {
+	// The process ID.
+	PID int
+	// Score is the badness score of the process, read from
+	// /proc/[pid]/oom_score. The process with the highest score is killed
+	// first.
+	Score int64
+	// ScoreAdj is the adjustment added to the badness score, in the range
+	// -1000 (never kill) to 1000, read from /proc/[pid]/oom_score_adj.
+	ScoreAdj int64
+	// Adj is the legacy adjustment in the range -17 (never kill) to 15,
+	// read from /proc/[pid]/oom_adj. It is derived from ScoreAdj by the
+	// kernel.
+	Adj int64
+}
+
+// OOM returns the OOM killer state of the process.
+func (p Proc) OOM() (ProcOOM, error) {
+	oom := ProcOOM{PID: p.PID}
+
+	for file, v := range map[string]*int64{
+		"oom_score":     &oom.Score,
+		"oom_score_adj": &oom.ScoreAdj,
+		"oom_adj":       &oom.Adj,
+	} {
+		val, err := util.ReadIntFromFile(p.path(file))
+		if err != nil {
+			return ProcOOM{}, err
+		}
+		*v = val
+	}
+
+	return oom, nil
+}
+
+// OOMRanking returns the OOM killer state of all processes, ordered by
+// descending badness score, i.e. the process the kernel would kill next comes
+// first. Processes that exit while the ranking is built are omitted.
+func (fs FS) OOMRanking() ([]ProcOOM, error) {
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := make([]ProcOOM, 0, len(procs))
+	for _, p := range procs {
+		oom, err := p.OOM()
+		if err != nil {
+			if isProcGone(err) {
+				continue
+			}
+			return nil, err
+		}
+		ranking = append(ranking, oom)
+	}
+
+	slices.SortFunc(ranking, func(a, b ProcOOM) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.PID, b.PID)
+	})
+
+	return ranking, nil
+}
+
+// OOMKills returns the number of processes killed by the OOM killer since
+// boot, read from the oom_kill line of /proc/vmstat.
+// Only available on kernel 4.13+.
+func (fs FS) OOMKills() (uint64, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path("vmstat"))
+	if err != nil {
+		return 0, err
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		key, value, ok := strings.Cut(s.Text(), " ")
+		if !ok || key != "oom_kill" {
+			continue
+		}
+		kills, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid oom_kill value %q: %w", ErrFileParse, value, err)
+		}
+		return kills, nil
+	}
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("%w: no oom_kill line in %s", ErrFileParse, fs.proc.Path("vmstat"))
+}
+
+// isProcGone reports whether err was caused by the process having exited.
+func isProcGone(err error) bool {
+	return errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ESRCH)
+}
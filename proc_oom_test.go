@@ -0,0 +1,64 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcOOM(t *testing.T) {
+	p, err := getProcFixtures(t).Proc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oom, err := p.OOM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ProcOOM{PID: 26231, Score: 25, ScoreAdj: -500, Adj: -8}
+	if diff := cmp.Diff(want, oom); diff != "" {
+		t.Errorf("unexpected OOM state (-want +got):\n%s", diff)
+	}
+}
+
+func TestOOMRanking(t *testing.T) {
+	ranking, err := getProcFixtures(t).OOMRanking()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ProcOOM{
+		{PID: 26232, Score: 1200, ScoreAdj: 1000, Adj: 15},
+		{PID: 26231, Score: 25, ScoreAdj: -500, Adj: -8},
+		{PID: 26233, Score: 0, ScoreAdj: -1000, Adj: -17},
+	}
+	if diff := cmp.Diff(want, ranking); diff != "" {
+		t.Errorf("unexpected OOM ranking (-want +got):\n%s", diff)
+	}
+}
+
+func TestOOMKills(t *testing.T) {
+	kills, err := getProcFixtures(t).OOMKills()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := uint64(3), kills; want != have {
+		t.Errorf("want oom_kill %d, have %d", want, have)
+	}
+}
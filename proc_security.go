@@ -0,0 +1,215 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// Capability is a Linux capability.
+//
+// See: https://www.kernel.org/doc/man-pages/online/pages/man7/capabilities.7.html
+type Capability uint
+
+// The capabilities known to this package, numbered as in
+// include/uapi/linux/capability.h.
+const (
+	CapChown Capability = iota
+	CapDACOverride
+	CapDACReadSearch
+	CapFowner
+	CapFsetid
+	CapKill
+	CapSetgid
+	CapSetuid
+	CapSetpcap
+	CapLinuxImmutable
+	CapNetBindService
+	CapNetBroadcast
+	CapNetAdmin
+	CapNetRaw
+	CapIPCLock
+	CapIPCOwner
+	CapSysModule
+	CapSysRawio
+	CapSysChroot
+	CapSysPtrace
+	CapSysPacct
+	CapSysAdmin
+	CapSysBoot
+	CapSysNice
+	CapSysResource
+	CapSysTime
+	CapSysTTYConfig
+	CapMknod
+	CapLease
+	CapAuditWrite
+	CapAuditControl
+	CapSetfcap
+	CapMacOverride
+	CapMacAdmin
+	CapSyslog
+	CapWakeAlarm
+	CapBlockSuspend
+	CapAuditRead
+	CapPerfmon
+	CapBPF
+	CapCheckpointRestore
+)
+
+var capabilityNames = [...]string{
+	"cap_chown",
+	"cap_dac_override",
+	"cap_dac_read_search",
+	"cap_fowner",
+	"cap_fsetid",
+	"cap_kill",
+	"cap_setgid",
+	"cap_setuid",
+	"cap_setpcap",
+	"cap_linux_immutable",
+	"cap_net_bind_service",
+	"cap_net_broadcast",
+	"cap_net_admin",
+	"cap_net_raw",
+	"cap_ipc_lock",
+	"cap_ipc_owner",
+	"cap_sys_module",
+	"cap_sys_rawio",
+	"cap_sys_chroot",
+	"cap_sys_ptrace",
+	"cap_sys_pacct",
+	"cap_sys_admin",
+	"cap_sys_boot",
+	"cap_sys_nice",
+	"cap_sys_resource",
+	"cap_sys_time",
+	"cap_sys_tty_config",
+	"cap_mknod",
+	"cap_lease",
+	"cap_audit_write",
+	"cap_audit_control",
+	"cap_setfcap",
+	"cap_mac_override",
+	"cap_mac_admin",
+	"cap_syslog",
+	"cap_wake_alarm",
+	"cap_block_suspend",
+	"cap_audit_read",
+	"cap_perfmon",
+	"cap_bpf",
+	"cap_checkpoint_restore",
+}
+
+// String returns the name of the capability as used by libcap, e.g.
+// "cap_sys_admin". Capabilities unknown to this package are named by their
+// number, e.g. "cap_41".
+func (c Capability) String() string {
+	if int(c) < len(capabilityNames) {
+		return capabilityNames[c]
+	}
+	return "cap_" + strconv.FormatUint(uint64(c), 10)
+}
+
+// CapabilitySet is a bitmap of capabilities, as found in the Cap* fields of
+// /proc/[pid]/status.
+type CapabilitySet uint64
+
+// Has reports whether the set contains the capability c.
+func (s CapabilitySet) Has(c Capability) bool {
+	return c < 64 && s&(1<<c) != 0
+}
+
+// Capabilities returns the capabilities contained in the set, in ascending
+// order.
+func (s CapabilitySet) Capabilities() []Capability {
+	var caps []Capability
+	for c := Capability(0); c < 64; c++ {
+		if s.Has(c) {
+			caps = append(caps, c)
+		}
+	}
+	return caps
+}
+
+// Names returns the names of the capabilities contained in the set, in
+// ascending order.
+func (s CapabilitySet) Names() []string {
+	caps := s.Capabilities()
+	names := make([]string, 0, len(caps))
+	for _, c := range caps {
+		names = append(names, c.String())
+	}
+	return names
+}
+
+// ProcCapabilities contains the capability sets of a process.
+type ProcCapabilities struct {
+	Inheritable CapabilitySet
+	Permitted   CapabilitySet
+	Effective   CapabilitySet
+	Bounding    CapabilitySet
+	Ambient     CapabilitySet
+}
+
+// Capabilities returns the capability sets of the process.
+func (s ProcStatus) Capabilities() ProcCapabilities {
+	return ProcCapabilities{
+		Inheritable: CapabilitySet(s.CapInh),
+		Permitted:   CapabilitySet(s.CapPrm),
+		Effective:   CapabilitySet(s.CapEff),
+		Bounding:    CapabilitySet(s.CapBnd),
+		Ambient:     CapabilitySet(s.CapAmb),
+	}
+}
+
+// SeccompMode is the seccomp mode of a process.
+type SeccompMode int
+
+// The seccomp modes reported in the Seccomp field of /proc/[pid]/status.
+const (
+	SeccompDisabled SeccompMode = 0
+	SeccompStrict   SeccompMode = 1
+	SeccompFilter   SeccompMode = 2
+)
+
+// String returns the name of the seccomp mode.
+func (m SeccompMode) String() string {
+	switch m {
+	case SeccompDisabled:
+		return "disabled"
+	case SeccompStrict:
+		return "strict"
+	case SeccompFilter:
+		return "filter"
+	default:
+		return "unknown (" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+// LSMLabel returns the security label of the process assigned by the active
+// Linux Security Module, e.g. "system_u:system_r:init_t:s0" for SELinux or
+// "unconfined" for AppArmor, read from /proc/[pid]/attr/current.
+func (p Proc) LSMLabel() (string, error) {
+	data, err := util.ReadFileNoStat(p.path("attr", "current"))
+	if err != nil {
+		return "", err
+	}
+
+	// SELinux terminates the label with a NUL byte, AppArmor with a newline.
+	return strings.TrimRight(string(data), "\x00\n"), nil
+}
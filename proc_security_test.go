@@ -0,0 +1,83 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCapabilitySet(t *testing.T) {
+	set := CapabilitySet(1<<CapNetBindService | 1<<CapSysAdmin | 1<<41)
+
+	if !set.Has(CapSysAdmin) || set.Has(CapChown) {
+		t.Errorf("unexpected capabilities in set %x: %v", uint64(set), set.Capabilities())
+	}
+
+	want := []string{"cap_net_bind_service", "cap_sys_admin", "cap_41"}
+	if diff := cmp.Diff(want, set.Names()); diff != "" {
+		t.Errorf("unexpected capability names (-want +got):\n%s", diff)
+	}
+}
+
+func TestProcStatusCapabilities(t *testing.T) {
+	p, err := getProcFixtures(t).Proc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.NewStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caps := s.Capabilities()
+	if caps.Inheritable != 0 || caps.Ambient != 0 {
+		t.Errorf("unexpected inheritable %x or ambient %x capabilities", uint64(caps.Inheritable), uint64(caps.Ambient))
+	}
+	effective := caps.Effective.Capabilities()
+	if want, have := 38, len(effective); want != have {
+		t.Fatalf("want %d effective capabilities, have %d", want, have)
+	}
+	if want, have := CapAuditRead, effective[len(effective)-1]; want != have {
+		t.Errorf("want last effective capability %s, have %s", want, have)
+	}
+}
+
+func TestProcLSMLabel(t *testing.T) {
+	p, err := getProcFixtures(t).Proc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+	label, err := p.LSMLabel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "system_u:system_r:prometheus_t:s0", label; want != have {
+		t.Errorf("want LSM label %q, have %q", want, have)
+	}
+}
+
+func TestSeccompModeString(t *testing.T) {
+	for mode, want := range map[SeccompMode]string{
+		SeccompDisabled: "disabled",
+		SeccompStrict:   "strict",
+		SeccompFilter:   "filter",
+		3:               "unknown (3)",
+	} {
+		if have := mode.String(); want != have {
+			t.Errorf("want seccomp mode %q, have %q", want, have)
+		}
+	}
+}
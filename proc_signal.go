@@ -0,0 +1,91 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strconv"
+	"syscall"
+)
+
+// The first real-time signal as numbered by the kernel. Note that the C
+// library reserves the first few real-time signals for internal use, so its
+// SIGRTMIN is usually higher. The last real-time signal, sigRTMax, depends on
+// the architecture.
+const sigRTMin = 32
+
+// SignalName returns the name of the signal, e.g. "SIGTERM", using the signal
+// numbering of the architecture. Real-time signals are named relative to the
+// first real-time signal of the kernel, e.g. "SIGRTMIN+2", except for the
+// last one, "SIGRTMAX".
+func SignalName(sig syscall.Signal) string {
+	switch {
+	case sig > 0 && sig < sigRTMin:
+		if name := standardSignalName(sig); name != "" {
+			return name
+		}
+	case sig == sigRTMin:
+		return "SIGRTMIN"
+	case sig == sigRTMax:
+		return "SIGRTMAX"
+	case sig > sigRTMin && sig < sigRTMax:
+		return "SIGRTMIN+" + strconv.Itoa(int(sig)-sigRTMin)
+	}
+	return "SIG" + strconv.Itoa(int(sig))
+}
+
+// SignalSet is a bitmap of signals, as found in the SigPnd, ShdPnd, SigBlk,
+// SigIgn and SigCgt fields of /proc/[pid]/status. Signal n is stored in bit
+// n-1.
+type SignalSet uint64
+
+// Has reports whether the set contains the signal sig.
+func (s SignalSet) Has(sig syscall.Signal) bool {
+	return sig > 0 && sig <= 64 && s&(1<<(sig-1)) != 0
+}
+
+// Signals returns the signals contained in the set, in ascending order.
+func (s SignalSet) Signals() []syscall.Signal {
+	var sigs []syscall.Signal
+	for sig := syscall.Signal(1); sig <= 64; sig++ {
+		if s.Has(sig) {
+			sigs = append(sigs, sig)
+		}
+	}
+	return sigs
+}
+
+// Names returns the names of the signals contained in the set, in ascending
+// order.
+func (s SignalSet) Names() []string {
+	sigs := s.Signals()
+	names := make([]string, 0, len(sigs))
+	for _, sig := range sigs {
+		names = append(names, SignalName(sig))
+	}
+	return names
+}
+
+func parseSignalSet(s string) (SignalSet, error) {
+	set, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	return SignalSet(set), nil
+}
+
+// PendingSignals returns the signals pending for the thread, either directed
+// at the thread itself or shared by the whole thread group.
+func (s ProcStatus) PendingSignals() SignalSet {
+	return s.SigPnd | s.ShdPnd
+}
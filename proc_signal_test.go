@@ -0,0 +1,119 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSignalSet(t *testing.T) {
+	set := SignalSet(1<<(15-1) | 1<<(9-1) | 1<<(34-1) | 1<<63)
+
+	if !set.Has(15) || !set.Has(9) || set.Has(2) || set.Has(0) || set.Has(65) {
+		t.Errorf("unexpected signals in set %x: %v", uint64(set), set.Signals())
+	}
+
+	want := []string{"SIGKILL", "SIGTERM", "SIGRTMIN+2", "SIGRTMAX"}
+	if diff := cmp.Diff(want, set.Names()); diff != "" {
+		t.Errorf("unexpected signal names (-want +got):\n%s", diff)
+	}
+}
+
+func TestSignalName(t *testing.T) {
+	if sigRTMax != 64 {
+		t.Skipf("SIGRTMAX is %d on this architecture", sigRTMax)
+	}
+
+	for sig, want := range map[syscall.Signal]string{
+		1:  "SIGHUP",
+		9:  "SIGKILL",
+		15: "SIGTERM",
+		32: "SIGRTMIN",
+		34: "SIGRTMIN+2",
+		63: "SIGRTMIN+31",
+		64: "SIGRTMAX",
+		65: "SIG65",
+		0:  "SIG0",
+	} {
+		if got := SignalName(sig); got != want {
+			t.Errorf("unexpected name for signal %d: want %s, got %s", sig, want, got)
+		}
+	}
+}
+
+func TestProcStatusSignals(t *testing.T) {
+	p, err := getProcFixtures(t).Proc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.NewStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "SigQueued", want: 8, have: s.SigQueued},
+		{name: "SigQueueLimit", want: 63965, have: s.SigQueueLimit},
+		{name: "SigPnd", want: 0, have: uint64(s.SigPnd)},
+		{name: "ShdPnd", want: 0, have: uint64(s.ShdPnd)},
+		{name: "SigBlk", want: 0x7be3c0fe28014a03, have: uint64(s.SigBlk)},
+		{name: "SigIgn", want: 0x1000, have: uint64(s.SigIgn)},
+		{name: "SigCgt", want: 0x1800004ec, have: uint64(s.SigCgt)},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %x, have %x", test.name, test.want, test.have)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"SIGPIPE"}, s.SigIgn.Names()); diff != "" {
+		t.Errorf("unexpected ignored signals (-want +got):\n%s", diff)
+	}
+	if !s.SigBlk.Has(syscall.Signal(15)) || s.SigBlk.Has(syscall.Signal(9)) {
+		t.Errorf("unexpected blocked signals: %v", s.SigBlk.Names())
+	}
+}
+
+func TestThreadStatusSignals(t *testing.T) {
+	thread, err := getProcFixtures(t).Thread(testPID, 27080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := thread.NewStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 27079, s.TGID; want != have {
+		t.Errorf("want TGID %d, have %d", want, have)
+	}
+	if want, have := uint64(2), s.SigQueued; want != have {
+		t.Errorf("want SigQueued %d, have %d", want, have)
+	}
+	if !s.SigBlk.Has(syscall.Signal(15)) {
+		t.Errorf("want SIGTERM blocked, have %v", s.SigBlk.Names())
+	}
+	if diff := cmp.Diff([]string{"SIGUSR1", "SIGTERM"}, s.PendingSignals().Names()); diff != "" {
+		t.Errorf("unexpected pending signals (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"SIGRTMIN", "SIGRTMIN+1"}, s.SigCgt.Names()); diff != "" {
+		t.Errorf("unexpected caught signals (-want +got):\n%s", diff)
+	}
+}
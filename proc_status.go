@@ -15,6 +15,7 @@ package procfs
 
 import (
 	"bytes"
+	"fmt"
 	"math/bits"
 	"slices"
 	"strconv"
@@ -96,6 +97,42 @@ type ProcStatus struct {
 	CapBnd uint64
 	// CapAmb is the bitmap of ambient capabilities
 	CapAmb uint64
+
+	// NoNewPrivs indicates whether the no_new_privs bit of the process is set.
+	NoNewPrivs bool
+	// Seccomp is the seccomp mode of the process.
+	Seccomp SeccompMode
+	// SeccompFilters is the number of seccomp filters attached to the process.
+	SeccompFilters uint64
+	// SpeculationStoreBypass is the state of the speculative store bypass
+	// mitigation, e.g. "thread force mitigated".
+	SpeculationStoreBypass string
+	// SpeculationIndirectBranch is the state of the indirect branch
+	// speculation mitigation, e.g. "conditional enabled".
+	SpeculationIndirectBranch string
+	// Umask is the file mode creation mask of the process.
+	// Only available on kernel 4.7+.
+	Umask uint32
+	// TracerPid is the PID of the process tracing this process, 0 if the
+	// process is not being traced.
+	TracerPid int
+
+	// SigQueued is the number of signals queued for the real user ID of the
+	// process.
+	SigQueued uint64
+	// SigQueueLimit is the limit on the number of queued signals of the real
+	// user ID of the process, see RLIMIT_SIGPENDING.
+	SigQueueLimit uint64
+	// SigPnd is the set of signals pending for the thread.
+	SigPnd SignalSet
+	// ShdPnd is the set of signals pending for the thread group.
+	ShdPnd SignalSet
+	// SigBlk is the set of blocked signals.
+	SigBlk SignalSet
+	// SigIgn is the set of ignored signals.
+	SigIgn SignalSet
+	// SigCgt is the set of caught signals.
+	SigCgt SignalSet
 }
 
 // NewStatus returns the current status information of the process.
@@ -233,6 +270,66 @@ func (s *ProcStatus) fillStatus(k string, vString string, vUint uint64, vUintByt
 		if err != nil {
 			return err
 		}
+	case "NoNewPrivs":
+		s.NoNewPrivs = vUint == 1
+	case "Seccomp":
+		s.Seccomp = SeccompMode(vUint)
+	case "Seccomp_filters":
+		s.SeccompFilters = vUint
+	case "Speculation_Store_Bypass":
+		s.SpeculationStoreBypass = vString
+	case "SpeculationIndirectBranch":
+		s.SpeculationIndirectBranch = vString
+	case "Umask":
+		umask, err := strconv.ParseUint(vString, 8, 32)
+		if err != nil {
+			return err
+		}
+		s.Umask = uint32(umask)
+	case "TracerPid":
+		s.TracerPid = int(vUint)
+	case "SigQ":
+		queued, limit, ok := strings.Cut(vString, "/")
+		if !ok {
+			return fmt.Errorf("%w: invalid SigQ %q", ErrFileParse, vString)
+		}
+		var err error
+		if s.SigQueued, err = strconv.ParseUint(queued, 10, 64); err != nil {
+			return err
+		}
+		if s.SigQueueLimit, err = strconv.ParseUint(limit, 10, 64); err != nil {
+			return err
+		}
+	case "SigPnd":
+		var err error
+		s.SigPnd, err = parseSignalSet(vString)
+		if err != nil {
+			return err
+		}
+	case "ShdPnd":
+		var err error
+		s.ShdPnd, err = parseSignalSet(vString)
+		if err != nil {
+			return err
+		}
+	case "SigBlk":
+		var err error
+		s.SigBlk, err = parseSignalSet(vString)
+		if err != nil {
+			return err
+		}
+	case "SigIgn":
+		var err error
+		s.SigIgn, err = parseSignalSet(vString)
+		if err != nil {
+			return err
+		}
+	case "SigCgt":
+		var err error
+		s.SigCgt, err = parseSignalSet(vString)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -14,6 +14,7 @@
 package procfs
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -188,3 +189,37 @@ func TestCaps(t *testing.T) {
 		}
 	}
 }
+
+func TestProcStatusSecurity(t *testing.T) {
+	p, err := getProcFixtures(t).Proc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := p.NewStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.NoNewPrivs {
+		t.Error("want NoNewPrivs set")
+	}
+	if want, have := SeccompFilter, s.Seccomp; want != have {
+		t.Errorf("want seccomp mode %s, have %s", want, have)
+	}
+	for _, test := range []struct {
+		name string
+		want string
+		have string
+	}{
+		{name: "SeccompFilters", want: "1", have: strconv.FormatUint(s.SeccompFilters, 10)},
+		{name: "SpeculationStoreBypass", want: "thread force mitigated", have: s.SpeculationStoreBypass},
+		{name: "SpeculationIndirectBranch", want: "conditional force disabled", have: s.SpeculationIndirectBranch},
+		{name: "Umask", want: "22", have: strconv.FormatUint(uint64(s.Umask), 8)},
+		{name: "TracerPid", want: "0", have: strconv.Itoa(s.TracerPid)},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %s, have %s", test.name, test.want, test.have)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sysfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs"
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// CgroupMemoryEvents contains the memory events of a cgroup v2 control group,
+// read from its memory.events file. The counters include the events of all
+// descendant control groups.
+//
+// See: https://docs.kernel.org/admin-guide/cgroup-v2.html#memory-interface-files
+type CgroupMemoryEvents struct {
+	// Low is the number of times the cgroup was reclaimed despite being
+	// below its low boundary.
+	Low uint64
+	// High is the number of times the processes of the cgroup were throttled
+	// because the high boundary was exceeded.
+	High uint64
+	// Max is the number of times the memory usage was about to exceed the
+	// max boundary.
+	Max uint64
+	// OOM is the number of times the memory usage hit the limit and
+	// allocations failed.
+	OOM uint64
+	// OOMKill is the number of processes of the cgroup killed by the OOM
+	// killer.
+	OOMKill uint64
+	// OOMGroupKill is the number of times a group OOM kill occurred.
+	// Only available on kernel 5.17+.
+	OOMGroupKill *uint64
+}
+
+// CgroupMemoryEvents returns the memory events of the cgroup v2 control group
+// at path, relative to the cgroup mount point /sys/fs/cgroup. See
+// ProcCgroupMemoryEvents for the control group of a process.
+func (fs FS) CgroupMemoryEvents(path string) (*CgroupMemoryEvents, error) {
+	data, err := util.ReadFileNoStat(fs.sys.Path("fs/cgroup", path, "memory.events"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCgroupMemoryEvents(data)
+}
+
+// ProcCgroupMemoryEvents returns the memory events of the cgroup v2 control
+// group of the process, e.g. to count the OOM kills within its cgroup. The
+// control group is the path of the "0::" entry of /proc/[pid]/cgroup.
+func (fs FS) ProcCgroupMemoryEvents(p procfs.Proc) (*CgroupMemoryEvents, error) {
+	cgroups, err := p.Cgroups()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cgroups {
+		if c.HierarchyID == 0 && len(c.Controllers) == 0 {
+			return fs.CgroupMemoryEvents(c.Path)
+		}
+	}
+
+	return nil, fmt.Errorf("process %d is not in a cgroup v2 hierarchy", p.PID)
+}
+
+func parseCgroupMemoryEvents(data []byte) (*CgroupMemoryEvents, error) {
+	var events CgroupMemoryEvents
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid memory.events line %q", s.Text())
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in memory.events: %w", err)
+		}
+
+		switch fields[0] {
+		case "low":
+			events.Low = v
+		case "high":
+			events.High = v
+		case "max":
+			events.Max = v
+		case "oom":
+			events.OOM = v
+		case "oom_kill":
+			events.OOMKill = v
+		case "oom_group_kill":
+			events.OOMGroupKill = &v
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return &events, nil
+}
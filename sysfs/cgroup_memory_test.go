@@ -0,0 +1,84 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sysfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/prometheus/procfs"
+)
+
+func TestCgroupMemoryEvents(t *testing.T) {
+	fs, err := NewFS(sysTestFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := fs.CgroupMemoryEvents("/system.slice/prometheus.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groupKills := uint64(0)
+	want := &CgroupMemoryEvents{
+		High:         12,
+		Max:          147,
+		OOM:          4,
+		OOMKill:      2,
+		OOMGroupKill: &groupKills,
+	}
+
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Fatalf("unexpected diff (-want +got):\n%s", diff)
+	}
+
+	if _, err := fs.CgroupMemoryEvents("/missing.slice"); err == nil {
+		t.Fatal("expected error for missing cgroup")
+	}
+}
+
+func TestProcCgroupMemoryEvents(t *testing.T) {
+	fs, err := NewFS(sysTestFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pfs, err := procfs.NewFS("testdata/fixtures/proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := pfs.Proc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := fs.ProcCgroupMemoryEvents(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := uint64(2), events.OOMKill; want != got {
+		t.Errorf("want %d OOM kills, got %d", want, got)
+	}
+
+	p, err = pfs.Proc(26232)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ProcCgroupMemoryEvents(p); err == nil {
+		t.Fatal("expected error for a process without a cgroup file")
+	}
+}
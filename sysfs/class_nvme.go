@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 
 	"github.com/prometheus/procfs/internal/util"
@@ -27,7 +28,10 @@ import (
 
 const nvmeClassPath = "class/nvme"
 
-var nvmeNamespacePattern = regexp.MustCompile(`nvme\d+n(\d+)`)
+var (
+	nvmeNamespacePattern   = regexp.MustCompile(`nvme\d+n(\d+)`)
+	nvmeTemperaturePattern = regexp.MustCompile(`^temp(\d+)_input$`)
+)
 
 // NVMeNamespace contains info from files in /sys/class/nvme/<device>/<namespace>.
 type NVMeNamespace struct {
@@ -39,17 +43,39 @@ type NVMeNamespace struct {
 	UsedBytes        uint64 // calculated: UsedBlocks * LogicalBlockSize
 	SizeBytes        uint64 // calculated: SizeBlocks * LogicalBlockSize
 	CapacityBytes    uint64 // calculated: SizeBlocks * LogicalBlockSize
+	NSID             uint64 // from nsid file
+	NGUID            string // from nguid file
+	UUID             string // from uuid file
+	WWID             string // from wwid file
+}
+
+// NVMeTemperature contains info from a temperature sensor of the hwmon device
+// linked to an NVMe controller in /sys/class/nvme/<device>/hwmon<id>.
+type NVMeTemperature struct {
+	Index int    // sensor index extracted from temp<index>_input
+	Label string // from temp<index>_label file
+	Temp  int64  // from temp<index>_input file, in millidegree Celsius
+	Min   *int64 // from temp<index>_min file, in millidegree Celsius
+	Max   *int64 // from temp<index>_max file, in millidegree Celsius
+	Crit  *int64 // from temp<index>_crit file, in millidegree Celsius
 }
 
 // NVMeDevice contains info from files in /sys/class/nvme for a single NVMe device.
 type NVMeDevice struct {
 	Name             string
-	Serial           string          // /sys/class/nvme/<Name>/serial
-	Model            string          // /sys/class/nvme/<Name>/model
-	State            string          // /sys/class/nvme/<Name>/state
-	FirmwareRevision string          // /sys/class/nvme/<Name>/firmware_rev
-	ControllerID     string          // /sys/class/nvme/<Name>/cntlid
-	Namespaces       []NVMeNamespace // NVMe namespaces for this device
+	Serial           string            // /sys/class/nvme/<Name>/serial
+	Model            string            // /sys/class/nvme/<Name>/model
+	State            string            // /sys/class/nvme/<Name>/state
+	FirmwareRevision string            // /sys/class/nvme/<Name>/firmware_rev
+	ControllerID     string            // /sys/class/nvme/<Name>/cntlid
+	Transport        string            // /sys/class/nvme/<Name>/transport
+	Address          string            // /sys/class/nvme/<Name>/address
+	QueueCount       uint64            // /sys/class/nvme/<Name>/queue_count
+	SQSize           uint64            // /sys/class/nvme/<Name>/sqsize
+	KATO             uint64            // /sys/class/nvme/<Name>/kato, keep alive timeout in seconds
+	NUMANode         int64             // /sys/class/nvme/<Name>/numa_node, -1 if not set
+	Namespaces       []NVMeNamespace   // NVMe namespaces for this device
+	Temperatures     []NVMeTemperature // /sys/class/nvme/<Name>/hwmon<id>/temp<index>_*
 }
 
 // NVMeClass is a collection of every NVMe device in /sys/class/nvme.
@@ -106,6 +132,49 @@ func (fs FS) parseNVMeDevice(name string) (*NVMeDevice, error) {
 		}
 	}
 
+	// Parse optional controller attributes, older kernels and some transports lack them
+	for f, p := range map[string]*string{
+		"transport": &device.Transport,
+		"address":   &device.Address,
+	} {
+		name := filepath.Join(path, f)
+		value, err := util.SysReadFile(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read file %q: %w", name, err)
+		}
+		*p = value
+	}
+	for f, p := range map[string]*uint64{
+		"queue_count": &device.QueueCount,
+		"sqsize":      &device.SQSize,
+		"kato":        &device.KATO,
+	} {
+		name := filepath.Join(path, f)
+		value, err := util.ReadUintFromFile(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read file %q: %w", name, err)
+		}
+		*p = value
+	}
+	device.NUMANode = -1
+	if numaNode, err := util.ReadIntFromFile(filepath.Join(path, "numa_node")); err == nil {
+		device.NUMANode = numaNode
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read file %q: %w", filepath.Join(path, "numa_node"), err)
+	}
+
+	temperatures, err := parseNVMeTemperatures(path)
+	if err != nil {
+		return nil, err
+	}
+	device.Temperatures = temperatures
+
 	// Parse namespaces - read directory and filter using regex
 	dirs, err := os.ReadDir(path)
 	if err != nil {
@@ -130,32 +199,43 @@ func (fs FS) parseNVMeDevice(name string) (*NVMeDevice, error) {
 		}
 
 		// Parse namespace attributes using the same approach as device attributes
-		for _, f := range [...]string{"nuse", "size", "queue/logical_block_size", "ana_state"} {
+		for _, f := range [...]string{"nuse", "size", "queue/logical_block_size", "ana_state", "nsid", "nguid", "uuid", "wwid"} {
 			filePath := filepath.Join(namespacePath, f)
 			value, err := util.SysReadFile(filePath)
 			if err != nil {
-				if f == "ana_state" {
-					// ana_state may not exist, skip silently
+				switch f {
+				case "ana_state", "nsid", "nguid", "uuid", "wwid":
+					// optional attributes may not exist, skip silently
 					continue
 				}
 				return nil, fmt.Errorf("failed to read file %q: %w", filePath, err)
 			}
 
+			var p *uint64
 			switch f {
 			case "nuse":
-				if val, parseErr := strconv.ParseUint(value, 10, 64); parseErr == nil {
-					namespace.UsedBlocks = val
-				}
+				p = &namespace.UsedBlocks
 			case "size":
-				if val, parseErr := strconv.ParseUint(value, 10, 64); parseErr == nil {
-					namespace.SizeBlocks = val
-				}
+				p = &namespace.SizeBlocks
 			case "queue/logical_block_size":
-				if val, parseErr := strconv.ParseUint(value, 10, 64); parseErr == nil {
-					namespace.LogicalBlockSize = val
-				}
+				p = &namespace.LogicalBlockSize
+			case "nsid":
+				p = &namespace.NSID
 			case "ana_state":
 				namespace.ANAState = value
+			case "nguid":
+				namespace.NGUID = value
+			case "uuid":
+				namespace.UUID = value
+			case "wwid":
+				namespace.WWID = value
+			}
+			if p != nil {
+				val, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse file %q: %w", filePath, err)
+				}
+				*p = val
 			}
 		}
 
@@ -171,3 +251,58 @@ func (fs FS) parseNVMeDevice(name string) (*NVMeDevice, error) {
 
 	return &device, nil
 }
+
+// Parse the temperature sensors of the hwmon device linked to an NVMe controller.
+func parseNVMeTemperatures(path string) ([]NVMeTemperature, error) {
+	hwmons, err := filepath.Glob(filepath.Join(path, "hwmon[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var temperatures []NVMeTemperature
+	for _, hwmon := range hwmons {
+		files, err := os.ReadDir(hwmon)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list hwmon sensors at %q: %w", hwmon, err)
+		}
+
+		for _, f := range files {
+			match := nvmeTemperaturePattern.FindStringSubmatch(f.Name())
+			if match == nil {
+				continue
+			}
+			index, err := strconv.Atoi(match[1])
+			if err != nil {
+				return nil, err
+			}
+
+			temp, err := util.SysReadIntFromFile(filepath.Join(hwmon, f.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read file %q: %w", filepath.Join(hwmon, f.Name()), err)
+			}
+			temperature := NVMeTemperature{Index: index, Temp: temp}
+
+			prefix := "temp" + match[1] + "_"
+			if label, err := util.SysReadFile(filepath.Join(hwmon, prefix+"label")); err == nil {
+				temperature.Label = label
+			}
+			for suffix, p := range map[string]**int64{
+				"min":  &temperature.Min,
+				"max":  &temperature.Max,
+				"crit": &temperature.Crit,
+			} {
+				if val, err := util.SysReadIntFromFile(filepath.Join(hwmon, prefix+suffix)); err == nil {
+					*p = &val
+				}
+			}
+
+			temperatures = append(temperatures, temperature)
+		}
+	}
+
+	slices.SortFunc(temperatures, func(a, b NVMeTemperature) int {
+		return a.Index - b.Index
+	})
+
+	return temperatures, nil
+}
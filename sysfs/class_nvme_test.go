@@ -18,6 +18,7 @@ package sysfs
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -48,6 +49,12 @@ func TestNVMeClass(t *testing.T) {
 			Serial:           "S680HF8N190894I",
 			State:            "live",
 			ControllerID:     "1997",
+			Transport:        "pcie",
+			Address:          "0000:01:00.0",
+			QueueCount:       9,
+			SQSize:           1023,
+			KATO:             0,
+			NUMANode:         -1,
 			Namespaces: []NVMeNamespace{
 				{
 					ID:               "0",
@@ -58,6 +65,27 @@ func TestNVMeClass(t *testing.T) {
 					UsedBytes:        2000000000000,
 					SizeBytes:        16000000000000,
 					CapacityBytes:    16000000000000,
+					NSID:             1,
+					NGUID:            "6479a776-5200-0213-0000-000000000000",
+					UUID:             "00000000-0000-0000-0000-000000000000",
+					WWID:             "eui.6479a7765200021300000000",
+				},
+			},
+			Temperatures: []NVMeTemperature{
+				{
+					Index: 1,
+					Label: "Composite",
+					Temp:  43850,
+					Min:   int64p(-150),
+					Max:   int64p(84850),
+					Crit:  int64p(94850),
+				},
+				{
+					Index: 2,
+					Label: "Sensor 1",
+					Temp:  43850,
+					Min:   int64p(-273150),
+					Max:   int64p(65261850),
 				},
 			},
 		},
@@ -412,3 +440,63 @@ func TestNVMeNamespaceMissingFiles(t *testing.T) {
 		t.Errorf("Expected LogicalBlockSize 4096, got %d", namespace.LogicalBlockSize)
 	}
 }
+
+func int64p(i int64) *int64 { return &i }
+
+func TestNVMeNamespaceInvalidAttributes(t *testing.T) {
+	for _, invalid := range []string{"nuse", "size", "queue/logical_block_size", "nsid"} {
+		t.Run(invalid, func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			deviceDir := filepath.Join(tempDir, "class", "nvme", "nvme0")
+			namespaceDir := filepath.Join(deviceDir, "nvme0n1")
+			err := os.MkdirAll(filepath.Join(namespaceDir, "queue"), 0o755)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceFiles := map[string]string{
+				"firmware_rev": "1B2QEXP7",
+				"model":        "Samsung SSD 970 PRO 512GB",
+				"serial":       "S680HF8N190894I",
+				"state":        "live",
+				"cntlid":       "1997",
+			}
+
+			for filename, content := range deviceFiles {
+				err := os.WriteFile(filepath.Join(deviceDir, filename), []byte(content), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			namespaceFiles := map[string]string{
+				"nuse":                     "123456",
+				"size":                     "1000215216",
+				"queue/logical_block_size": "512",
+				"nsid":                     "1",
+			}
+			namespaceFiles[invalid] = "invalid"
+
+			for filename, content := range namespaceFiles {
+				err := os.WriteFile(filepath.Join(namespaceDir, filename), []byte(content), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fs, err := NewFS(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = fs.NVMeClass()
+			if err == nil {
+				t.Fatalf("expected an error for an invalid %s, but none occurred", invalid)
+			}
+			if want := filepath.Join(namespaceDir, invalid); !strings.Contains(err.Error(), want) {
+				t.Fatalf("expected a parse error for %s, got: %v", want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sysfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/procfs"
+	"github.com/prometheus/procfs/internal/util"
+)
+
+const moduleClassPath = "module"
+
+// Module contains info from files in /sys/module for a single kernel module.
+// Modules built into the kernel only appear if they have parameters or a
+// version, and lack the attributes of loadable modules.
+// https://www.kernel.org/doc/Documentation/ABI/testing/sysfs-module
+type Module struct {
+	Name       string
+	Version    *string // /sys/module/<Name>/version
+	SrcVersion *string // /sys/module/<Name>/srcversion
+	Taint      *string // /sys/module/<Name>/taint
+	RefCnt     *int64  // /sys/module/<Name>/refcnt
+	InitState  *string // /sys/module/<Name>/initstate
+	CoreSize   *uint64 // /sys/module/<Name>/coresize
+	// Parameters maps the names of the readable module parameters to their
+	// values, read from /sys/module/<Name>/parameters.
+	Parameters map[string]string
+	// Holders are the names of the modules using this module, read from
+	// /sys/module/<Name>/holders.
+	Holders []string
+}
+
+// LoadedModule is a loaded kernel module listed in /proc/modules, together
+// with the attributes of the module in /sys/module/<Name>. The taint flags
+// are part of both and taken from /proc/modules.
+type LoadedModule struct {
+	procfs.Module
+	Version    *string // /sys/module/<Name>/version
+	SrcVersion *string // /sys/module/<Name>/srcversion
+	RefCnt     *int64  // /sys/module/<Name>/refcnt
+	InitState  *string // /sys/module/<Name>/initstate
+	CoreSize   *uint64 // /sys/module/<Name>/coresize
+	Parameters map[string]string
+}
+
+// Modules returns info for all kernel modules read from /sys/module, keyed by
+// module name. It complements the list of loaded modules in /proc/modules.
+func (fs FS) Modules() (map[string]Module, error) {
+	path := fs.sys.Path(moduleClassPath)
+
+	dirs, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modules at %q: %w", path, err)
+	}
+
+	modules := make(map[string]Module, len(dirs))
+	for _, d := range dirs {
+		module, err := fs.Module(d.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		modules[module.Name] = *module
+	}
+
+	return modules, nil
+}
+
+// Module returns info for a single kernel module read from
+// /sys/module/<name>.
+func (fs FS) Module(name string) (*Module, error) {
+	path := fs.sys.Path(moduleClassPath, name)
+	module := Module{Name: name}
+
+	for _, f := range [...]string{"version", "srcversion", "taint", "refcnt", "initstate", "coresize"} {
+		file := filepath.Join(path, f)
+		value, err := util.SysReadFile(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read file %q: %w", file, err)
+		}
+
+		vp := util.NewValueParser(value)
+
+		switch f {
+		case "version":
+			module.Version = &value
+		case "srcversion":
+			module.SrcVersion = &value
+		case "taint":
+			module.Taint = &value
+		case "refcnt":
+			module.RefCnt = vp.PInt64()
+		case "initstate":
+			module.InitState = &value
+		case "coresize":
+			module.CoreSize = vp.PUInt64()
+		}
+
+		if err := vp.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	params, err := os.ReadDir(filepath.Join(path, "parameters"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list parameters of module %q: %w", name, err)
+	}
+	for _, p := range params {
+		if module.Parameters == nil {
+			module.Parameters = make(map[string]string, len(params))
+		}
+		value, err := util.SysReadFile(filepath.Join(path, "parameters", p.Name()))
+		if err != nil {
+			// Some parameters are write-only or only readable by root.
+			if os.IsPermission(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read parameter %q of module %q: %w", p.Name(), name, err)
+		}
+		module.Parameters[p.Name()] = value
+	}
+
+	holders, err := os.ReadDir(filepath.Join(path, "holders"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list holders of module %q: %w", name, err)
+	}
+	for _, h := range holders {
+		module.Holders = append(module.Holders, h.Name())
+	}
+
+	return &module, nil
+}
+
+// LoadedModules joins the loaded modules, as returned by procfs.FS.Modules,
+// with their attributes read from /sys/module. The attributes of modules
+// missing from /sys/module are left unset.
+func (fs FS) LoadedModules(modules []procfs.Module) ([]LoadedModule, error) {
+	loaded := make([]LoadedModule, 0, len(modules))
+	for _, m := range modules {
+		module, err := fs.Module(m.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		loaded = append(loaded, LoadedModule{
+			Module:     m,
+			Version:    module.Version,
+			SrcVersion: module.SrcVersion,
+			RefCnt:     module.RefCnt,
+			InitState:  module.InitState,
+			CoreSize:   module.CoreSize,
+			Parameters: module.Parameters,
+		})
+	}
+
+	return loaded, nil
+}
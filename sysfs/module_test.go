@@ -0,0 +1,138 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sysfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/prometheus/procfs"
+)
+
+func TestModules(t *testing.T) {
+	fs, err := NewFS(sysTestFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules, err := fs.Modules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		version    = "550.54.14"
+		srcversion = "1D2A9F0E3C5B7A8D9E0F123"
+		taint      = "POE"
+		refcnt     = int64(45)
+		initstate  = "live"
+		coresize   = uint64(56115200)
+	)
+	want := map[string]Module{
+		"nvidia": {
+			Name:       "nvidia",
+			Version:    &version,
+			SrcVersion: &srcversion,
+			Taint:      &taint,
+			RefCnt:     &refcnt,
+			InitState:  &initstate,
+			CoreSize:   &coresize,
+			Parameters: map[string]string{
+				"NVreg_EnableMSI":                   "1",
+				"NVreg_OpenRmEnableUnsupportedGpus": "0",
+			},
+			Holders: []string{"nvidia_drm"},
+		},
+		"printk": {
+			Name: "printk",
+			Parameters: map[string]string{
+				"always_kmsg_dump": "N",
+				"time":             "Y",
+			},
+		},
+	}
+
+	if want, got := 5, len(modules); want != got {
+		t.Fatalf("want %d modules, got %d", want, got)
+	}
+	for name, m := range want {
+		if diff := cmp.Diff(m, modules[name]); diff != "" {
+			t.Errorf("unexpected module %s (-want +got):\n%s", name, diff)
+		}
+	}
+
+	if got := *modules["e1000e"].Taint; got != "" {
+		t.Errorf("want untainted e1000e, got taint %q", got)
+	}
+}
+
+func TestLoadedModules(t *testing.T) {
+	fs, err := NewFS(sysTestFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules := []procfs.Module{
+		{Name: "nvidia", Size: 56115200, RefCount: 45, UsedBy: []string{"nvidia_drm"}, State: "Live", Taints: "POE"},
+		{Name: "zfs", Size: 4071424, RefCount: 1, State: "Loading", Taints: "POE"},
+		{Name: "video", Size: 65536, RefCount: 2, State: "Live"},
+	}
+	loaded, err := fs.LoadedModules(modules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		nvidiaVersion    = "550.54.14"
+		nvidiaSrcVersion = "1D2A9F0E3C5B7A8D9E0F123"
+		nvidiaRefCnt     = int64(45)
+		nvidiaInitState  = "live"
+		nvidiaCoreSize   = uint64(56115200)
+		zfsVersion       = "2.2.2-1"
+		zfsSrcVersion    = "6C8D7B1A2E3F4A5B6C7D8E9"
+		zfsRefCnt        = int64(1)
+		zfsInitState     = "coming"
+		zfsCoreSize      = uint64(4071424)
+	)
+	want := []LoadedModule{
+		{
+			Module:     modules[0],
+			Version:    &nvidiaVersion,
+			SrcVersion: &nvidiaSrcVersion,
+			RefCnt:     &nvidiaRefCnt,
+			InitState:  &nvidiaInitState,
+			CoreSize:   &nvidiaCoreSize,
+			Parameters: map[string]string{
+				"NVreg_EnableMSI":                   "1",
+				"NVreg_OpenRmEnableUnsupportedGpus": "0",
+			},
+		},
+		{
+			Module:     modules[1],
+			Version:    &zfsVersion,
+			SrcVersion: &zfsSrcVersion,
+			RefCnt:     &zfsRefCnt,
+			InitState:  &zfsInitState,
+			CoreSize:   &zfsCoreSize,
+			Parameters: map[string]string{"zfs_arc_max": "0"},
+		},
+		{Module: modules[2]},
+	}
+	if diff := cmp.Diff(want, loaded); diff != "" {
+		t.Errorf("unexpected loaded modules (-want +got):\n%s", diff)
+	}
+}
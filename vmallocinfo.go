@@ -0,0 +1,182 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// VmallocArea is a virtually contiguous kernel memory area, parsed from a
+// line of /proc/vmallocinfo.
+type VmallocArea struct {
+	// Start and End are the virtual address range of the area. Both are 0
+	// unless read with root privileges.
+	Start uint64
+	End   uint64
+	// Size is the size of the area in bytes, including the guard page.
+	Size uint64
+	// Caller is the function that allocated the area, e.g.
+	// "dup_task_struct+0x51/0x190", followed by the module name in brackets
+	// if the function belongs to a module. Empty if unknown.
+	Caller string
+	// Pages is the number of pages backing the area.
+	Pages uint64
+	// Phys is the physical address mapped by an ioremap area.
+	Phys uint64
+	// Flags are the type flags of the area, e.g. "ioremap", "vmalloc",
+	// "vmap", "user" or "vpages".
+	Flags []string
+	// NodePages maps each NUMA node to the number of pages of the area
+	// allocated on it.
+	NodePages map[int]uint64
+}
+
+// HasFlag reports whether the area has the type flag f, e.g. "vmalloc".
+func (a VmallocArea) HasFlag(f string) bool {
+	return slices.Contains(a.Flags, f)
+}
+
+// VmallocCaller contains the vmalloc areas allocated by a caller.
+type VmallocCaller struct {
+	Caller string
+	// Areas is the number of areas allocated by the caller.
+	Areas uint64
+	// Size is the total size of the areas in bytes.
+	Size uint64
+	// Pages is the total number of pages backing the areas.
+	Pages uint64
+}
+
+// VmallocInfo reads the vmalloc areas from /proc/vmallocinfo.
+func (fs FS) VmallocInfo() ([]VmallocArea, error) {
+	data, err := util.ReadFileNoStat(fs.proc.Path("vmallocinfo"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVmallocInfo(bytes.NewReader(data))
+}
+
+// VmallocByCaller aggregates vmalloc areas by caller, ordered by descending
+// total size.
+func VmallocByCaller(areas []VmallocArea) []VmallocCaller {
+	index := map[string]int{}
+	var callers []VmallocCaller
+	for _, a := range areas {
+		i, ok := index[a.Caller]
+		if !ok {
+			i = len(callers)
+			index[a.Caller] = i
+			callers = append(callers, VmallocCaller{Caller: a.Caller})
+		}
+		callers[i].Areas++
+		callers[i].Size += a.Size
+		callers[i].Pages += a.Pages
+	}
+
+	slices.SortStableFunc(callers, func(a, b VmallocCaller) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+
+	return callers
+}
+
+// parseVmallocInfo parses /proc/vmallocinfo. Lines look like:
+//
+//	0xffffa9c1c0008000-0xffffa9c1c000d000   20480 dup_task_struct+0x51/0x190 pages=4 vmalloc N0=4
+func parseVmallocInfo(r io.Reader) ([]VmallocArea, error) {
+	areas := []VmallocArea{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("%w: invalid vmallocinfo line %q", ErrFileParse, line)
+		}
+
+		var (
+			area VmallocArea
+			err  error
+		)
+		start, end, ok := strings.Cut(parts[0], "-")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid vmallocinfo address range %q", ErrFileParse, parts[0])
+		}
+		if area.Start, err = strconv.ParseUint(strings.TrimPrefix(start, "0x"), 16, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid vmallocinfo line %q: %w", ErrFileParse, line, err)
+		}
+		if area.End, err = strconv.ParseUint(strings.TrimPrefix(end, "0x"), 16, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid vmallocinfo line %q: %w", ErrFileParse, line, err)
+		}
+		if area.Size, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid vmallocinfo line %q: %w", ErrFileParse, line, err)
+		}
+
+		for i, p := range parts[2:] {
+			key, value, hasValue := strings.Cut(p, "=")
+			switch {
+			case i == 0 && (strings.Contains(p, "+0x") || strings.HasPrefix(p, "0x")):
+				area.Caller = p
+			case i == 1 && area.Caller != "" && strings.HasPrefix(p, "["):
+				area.Caller += " " + p
+			case hasValue && key == "pages":
+				if area.Pages, err = strconv.ParseUint(value, 10, 64); err != nil {
+					return nil, fmt.Errorf("%w: invalid vmallocinfo line %q: %w", ErrFileParse, line, err)
+				}
+			case hasValue && key == "phys":
+				if area.Phys, err = strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 64); err != nil {
+					return nil, fmt.Errorf("%w: invalid vmallocinfo line %q: %w", ErrFileParse, line, err)
+				}
+			case hasValue && strings.HasPrefix(key, "N"):
+				node, err := strconv.Atoi(key[1:])
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid vmallocinfo line %q: %w", ErrFileParse, line, err)
+				}
+				pages, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid vmallocinfo line %q: %w", ErrFileParse, line, err)
+				}
+				if area.NodePages == nil {
+					area.NodePages = map[int]uint64{}
+				}
+				area.NodePages[node] = pages
+			default:
+				// Areas without caller are described by words, e.g.
+				// "vm_map_ram" or "unpurged vm_area".
+				area.Flags = append(area.Flags, p)
+			}
+		}
+
+		areas = append(areas, area)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return areas, nil
+}
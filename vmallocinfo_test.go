@@ -0,0 +1,109 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVmallocInfo(t *testing.T) {
+	areas, err := getProcFixtures(t).VmallocInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 10, len(areas); want != got {
+		t.Fatalf("want %d areas, got %d", want, got)
+	}
+
+	for _, test := range []struct {
+		index int
+		want  VmallocArea
+	}{
+		{
+			index: 0,
+			want: VmallocArea{
+				Start:  0xffffa9c1c0000000,
+				End:    0xffffa9c1c0002000,
+				Size:   8192,
+				Caller: "hpet_enable+0x3b/0x2c0",
+				Phys:   0xfed00000,
+				Flags:  []string{"ioremap"},
+			},
+		},
+		{
+			index: 4,
+			want: VmallocArea{
+				Start:     0xffffa9c1c000d000,
+				End:       0xffffa9c1c0012000,
+				Size:      20480,
+				Caller:    "dup_task_struct+0x51/0x190",
+				Pages:     4,
+				Flags:     []string{"vmalloc"},
+				NodePages: map[int]uint64{0: 3, 1: 1},
+			},
+		},
+		{
+			index: 6,
+			want: VmallocArea{
+				Start:  0xffffa9c1c0015000,
+				End:    0xffffa9c1c0017000,
+				Size:   8192,
+				Caller: "nvkm_mem_map_host+0x9e/0x130 [nouveau]",
+				Pages:  1,
+				Flags:  []string{"vmap"},
+			},
+		},
+		{
+			index: 7,
+			want: VmallocArea{
+				Start: 0xffffa9c1c0400000,
+				End:   0xffffa9c1c0600000,
+				Size:  2097152,
+				Flags: []string{"vm_map_ram"},
+			},
+		},
+	} {
+		if diff := cmp.Diff(test.want, areas[test.index]); diff != "" {
+			t.Errorf("unexpected area %d (-want +got):\n%s", test.index, diff)
+		}
+	}
+
+	if !areas[5].HasFlag("user") || areas[5].HasFlag("ioremap") {
+		t.Errorf("unexpected flags %v", areas[5].Flags)
+	}
+}
+
+func TestVmallocByCaller(t *testing.T) {
+	areas, err := getProcFixtures(t).VmallocInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callers := VmallocByCaller(areas)
+	if want, got := 8, len(callers); want != got {
+		t.Fatalf("want %d callers, got %d", want, got)
+	}
+
+	want := []VmallocCaller{
+		{Caller: "", Areas: 2, Size: 4194304},
+		{Caller: "alloc_large_system_hash+0x1a0/0x26b", Areas: 1, Size: 2101248, Pages: 512},
+		{Caller: "dup_task_struct+0x51/0x190", Areas: 2, Size: 40960, Pages: 8},
+	}
+	if diff := cmp.Diff(want, callers[:3]); diff != "" {
+		t.Errorf("unexpected callers (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xfs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// ErrorConfig contains the error handling configuration of an XFS filesystem,
+// parsed from /sys/fs/xfs/<dev>/error.
+// See Documentation/admin-guide/xfs.rst in the Linux kernel for more
+// information.
+type ErrorConfig struct {
+	// FailAtUnmount indicates if pending metadata IO retries are cancelled
+	// when the filesystem is unmounted.
+	FailAtUnmount bool
+	// Metadata contains the configuration for metadata IO errors, keyed by
+	// error class, e.g. "default", "EIO", "ENOSPC" or "ENODEV".
+	Metadata map[string]ErrorClassConfig
+}
+
+// ErrorClassConfig contains the retry configuration for a class of metadata
+// IO errors.
+type ErrorClassConfig struct {
+	// MaxRetries is the number of times an IO is retried before the
+	// filesystem is shut down, -1 means retry forever.
+	MaxRetries int64
+	// RetryTimeoutSeconds is the time an IO is retried before the filesystem
+	// is shut down, -1 means retry forever.
+	RetryTimeoutSeconds int64
+}
+
+// LogState contains the state of the log of an XFS filesystem, parsed from
+// /sys/fs/xfs/<dev>/log.
+type LogState struct {
+	// HeadLSN and TailLSN are the log sequence numbers of the head and the
+	// tail of the log.
+	HeadLSN LSN
+	TailLSN LSN
+
+	// ReserveGrantHead and WriteGrantHead are the positions of the grant
+	// heads, reported by older kernels only.
+	ReserveGrantHead *GrantHead
+	WriteGrantHead   *GrantHead
+
+	// ReserveGrantHeadBytes and WriteGrantHeadBytes are the number of log
+	// bytes reserved by the grant heads, reported by newer kernels only.
+	ReserveGrantHeadBytes *uint64
+	WriteGrantHeadBytes   *uint64
+}
+
+// LSN is an XFS log sequence number.
+type LSN struct {
+	// Cycle is the number of times the log has wrapped.
+	Cycle uint64
+	// Block is the basic block offset within the log.
+	Block uint64
+}
+
+// GrantHead is the position of an XFS log grant head.
+type GrantHead struct {
+	// Cycle is the number of times the grant head has wrapped.
+	Cycle uint64
+	// Bytes is the byte offset within the log.
+	Bytes uint64
+}
+
+// SysErrorConfig retrieves the error handling configuration of the named XFS
+// filesystem. Only available on kernel 4.7+.
+func (fs FS) SysErrorConfig(name string) (ErrorConfig, error) {
+	failAtUnmount, err := util.ReadUintFromFile(fs.sys.Path("fs/xfs", name, "error/fail_at_unmount"))
+	if err != nil {
+		return ErrorConfig{}, err
+	}
+
+	classes, err := os.ReadDir(fs.sys.Path("fs/xfs", name, "error/metadata"))
+	if err != nil {
+		return ErrorConfig{}, err
+	}
+
+	config := ErrorConfig{
+		FailAtUnmount: failAtUnmount == 1,
+		Metadata:      make(map[string]ErrorClassConfig, len(classes)),
+	}
+	for _, class := range classes {
+		if !class.IsDir() {
+			continue
+		}
+
+		var c ErrorClassConfig
+		for file, p := range map[string]*int64{
+			"max_retries":           &c.MaxRetries,
+			"retry_timeout_seconds": &c.RetryTimeoutSeconds,
+		} {
+			val, err := util.ReadIntFromFile(fs.sys.Path("fs/xfs", name, "error/metadata", class.Name(), file))
+			if err != nil {
+				return ErrorConfig{}, err
+			}
+			*p = val
+		}
+		config.Metadata[class.Name()] = c
+	}
+
+	return config, nil
+}
+
+// SysLogState retrieves the log state of the named XFS filesystem. Only
+// available on kernel 4.6+.
+func (fs FS) SysLogState(name string) (LogState, error) {
+	var state LogState
+	for file, p := range map[string]*LSN{
+		"log_head_lsn": &state.HeadLSN,
+		"log_tail_lsn": &state.TailLSN,
+	} {
+		s, err := util.SysReadFile(fs.sys.Path("fs/xfs", name, "log", file))
+		if err != nil {
+			return LogState{}, err
+		}
+		if _, err := fmt.Sscanf(s, "%d:%d", &p.Cycle, &p.Block); err != nil {
+			return LogState{}, fmt.Errorf("failed to parse %s %q: %w", file, s, err)
+		}
+	}
+
+	// Older kernels report the grant heads as cycle:bytes.
+	for file, p := range map[string]**GrantHead{
+		"reserve_grant_head": &state.ReserveGrantHead,
+		"write_grant_head":   &state.WriteGrantHead,
+	} {
+		s, err := util.SysReadFile(fs.sys.Path("fs/xfs", name, "log", file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return LogState{}, err
+		}
+		var h GrantHead
+		if _, err := fmt.Sscanf(s, "%d:%d", &h.Cycle, &h.Bytes); err != nil {
+			return LogState{}, fmt.Errorf("failed to parse %s %q: %w", file, s, err)
+		}
+		*p = &h
+	}
+
+	// Newer kernels report the grant heads in bytes.
+	for file, p := range map[string]**uint64{
+		"reserve_grant_head_bytes": &state.ReserveGrantHeadBytes,
+		"write_grant_head_bytes":   &state.WriteGrantHeadBytes,
+	} {
+		val, err := util.ReadUintFromFile(fs.sys.Path("fs/xfs", name, "log", file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return LogState{}, err
+		}
+		*p = &val
+	}
+
+	return state, nil
+}
+
+// ClearSysStats resets the statistics of the named XFS filesystem by writing
+// to /sys/fs/xfs/<name>/stats/stats_clear. Unlike the other functions of this
+// package it modifies kernel state and requires root privileges.
+func (fs FS) ClearSysStats(name string) error {
+	f, err := os.OpenFile(fs.sys.Path("fs/xfs", name, "stats/stats_clear"), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString("1"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
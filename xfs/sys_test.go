@@ -0,0 +1,121 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/prometheus/procfs/xfs"
+)
+
+func TestSysErrorConfig(t *testing.T) {
+	fs, err := xfs.NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access xfs fs: %v", err)
+	}
+	config, err := fs.SysErrorConfig("sda1")
+	if err != nil {
+		t.Fatalf("failed to parse XFS error config: %v", err)
+	}
+
+	want := xfs.ErrorConfig{
+		FailAtUnmount: true,
+		Metadata: map[string]xfs.ErrorClassConfig{
+			"default": {MaxRetries: -1, RetryTimeoutSeconds: -1},
+			"EIO":     {MaxRetries: -1, RetryTimeoutSeconds: 30},
+			"ENOSPC":  {MaxRetries: -1, RetryTimeoutSeconds: -1},
+			"ENODEV":  {MaxRetries: 0, RetryTimeoutSeconds: -1},
+		},
+	}
+	if diff := cmp.Diff(want, config); diff != "" {
+		t.Fatalf("unexpected XFS error config (-want +got):\n%s", diff)
+	}
+}
+
+func TestSysLogState(t *testing.T) {
+	fs, err := xfs.NewFS("testdata/fixtures/proc", "testdata/fixtures/sys")
+	if err != nil {
+		t.Fatalf("failed to access xfs fs: %v", err)
+	}
+
+	reserveBytes, writeBytes := uint64(262144), uint64(131072)
+	tests := []struct {
+		name string
+		want xfs.LogState
+	}{
+		{
+			name: "sda1",
+			want: xfs.LogState{
+				HeadLSN:          xfs.LSN{Cycle: 3, Block: 11216},
+				TailLSN:          xfs.LSN{Cycle: 3, Block: 10968},
+				ReserveGrantHead: &xfs.GrantHead{Cycle: 3, Bytes: 5742592},
+				WriteGrantHead:   &xfs.GrantHead{Cycle: 3, Bytes: 5742592},
+			},
+		},
+		{
+			name: "sdb1",
+			want: xfs.LogState{
+				HeadLSN:               xfs.LSN{Cycle: 12, Block: 2048},
+				TailLSN:               xfs.LSN{Cycle: 12, Block: 1024},
+				ReserveGrantHeadBytes: &reserveBytes,
+				WriteGrantHeadBytes:   &writeBytes,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		state, err := fs.SysLogState(tt.name)
+		if err != nil {
+			t.Fatalf("failed to parse XFS log state: %v", err)
+		}
+		if diff := cmp.Diff(tt.want, state); diff != "" {
+			t.Errorf("unexpected XFS log state for %s (-want +got):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestClearSysStats(t *testing.T) {
+	sys := t.TempDir()
+	statsClear := filepath.Join(sys, "fs/xfs/sda1/stats/stats_clear")
+	if err := os.MkdirAll(filepath.Dir(statsClear), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(statsClear, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := xfs.NewFS(sys, sys)
+	if err != nil {
+		t.Fatalf("failed to access xfs fs: %v", err)
+	}
+	if err := fs.ClearSysStats("sda1"); err != nil {
+		t.Fatalf("failed to clear XFS stats: %v", err)
+	}
+
+	got, err := os.ReadFile(statsClear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "1" {
+		t.Errorf("unexpected stats_clear content: %q", got)
+	}
+
+	if err := fs.ClearSysStats("sdb1"); err == nil {
+		t.Error("expected error clearing stats of missing filesystem")
+	}
+}